@@ -0,0 +1,108 @@
+// Package boltcache stores the gonbp exchange rates cache in a single bbolt database file
+//
+// It is a separate package, so that only the programs using it depend on bbolt.
+package boltcache
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Cache stores all values in a single bbolt database file, it implements cache.Cache
+//
+// The key segments map to nested buckets under the top-level "gonbp" bucket, e.g. key "A/EUR/2022-04-15" is stored
+// as "2022-04-15" in bucket "gonbp/A/EUR". The database file is locked by the process which opened it, see Open.
+type Cache struct {
+	db *bolt.DB
+}
+
+// openTimeout is how long Open waits for another process to release the database file
+const openTimeout = 5 * time.Second
+
+// Open opens or creates the database file at a given path
+//
+// Only one process can have the file open at a time, Open waits up to 5 seconds for the other process to close it.
+// The caller should Close the returned *Cache.
+func Open(path string) (*Cache, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{db: db}, nil
+}
+
+// Close closes the database file
+func (b *Cache) Close() error {
+	return b.db.Close()
+}
+
+// rootBucket is the top-level bucket, bbolt doesn't allow plain values outside of buckets
+const rootBucket = "gonbp"
+
+func splitKey(key string) ([]string, string) {
+	segments := append([]string{rootBucket}, strings.Split(key, "/")...)
+	return segments[:len(segments)-1], segments[len(segments)-1]
+}
+
+// Get returns the value stored under a given key
+func (b *Cache) Get(key string) ([]byte, bool, error) {
+	buckets, name := splitKey(key)
+	var value []byte
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Cursor().Bucket()
+		for _, n := range buckets {
+			if bucket = bucket.Bucket([]byte(n)); bucket == nil {
+				return nil
+			}
+		}
+		if v := bucket.Get([]byte(name)); v != nil {
+			value = append([]byte(nil), v...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return value, value != nil, nil
+}
+
+// Set stores the value under a given key, creating the buckets if needed
+func (b *Cache) Set(key string, value []byte) error {
+	buckets, name := splitKey(key)
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Cursor().Bucket()
+		for _, n := range buckets {
+			var err error
+			if bucket, err = bucket.CreateBucketIfNotExists([]byte(n)); err != nil {
+				return err
+			}
+		}
+		return bucket.Put([]byte(name), value)
+	})
+}
+
+// ImportFiles copies all values from a cache.File cache under dir, e.g. to migrate an existing cache to a single file
+func (b *Cache) ImportFiles(dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(p) != ".json" {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		value, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return b.Set(strings.TrimSuffix(filepath.ToSlash(rel), ".json"), value)
+	})
+}
@@ -0,0 +1,99 @@
+package boltcache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/igor-kupczynski/gonbp/cache"
+	"github.com/igor-kupczynski/gonbp/cache/boltcache"
+)
+
+var _ cache.Cache = (*boltcache.Cache)(nil)
+
+func TestCache(t *testing.T) {
+	base, err := os.MkdirTemp("", "gonbp-TestCache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(base)
+
+	b, err := boltcache.Open(filepath.Join(base, "nbp.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v, want no error", err)
+	}
+	defer b.Close()
+
+	t.Run("read non-existing value", func(t *testing.T) {
+		got, ok, err := b.Get("A/EUR/2022-04-15")
+		if err != nil || ok || got != nil {
+			t.Errorf("Get() = %q, %v, %v, want a miss", got, ok, err)
+		}
+	})
+
+	t.Run("read value previously set", func(t *testing.T) {
+		for _, value := range []string{`{"Rates":null}`, `{}`} {
+			if err := b.Set("A/EUR/2022-04-15", []byte(value)); err != nil {
+				t.Fatalf("Set() error = %v, want no error", err)
+			}
+			got, ok, err := b.Get("A/EUR/2022-04-15")
+			if err != nil || !ok {
+				t.Fatalf("Get() = %q, %v, %v, want a hit", got, ok, err)
+			}
+			if diff := cmp.Diff(value, string(got)); diff != "" {
+				t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+			}
+		}
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		entries, err := os.ReadDir(base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 {
+			t.Errorf("Expected a single file, got %v", entries)
+		}
+	})
+}
+
+func TestCache_ImportFiles(t *testing.T) {
+	base, err := os.MkdirTemp("", "gonbp-TestCache_ImportFiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(base)
+
+	files := cache.NewFile(filepath.Join(base, "files"))
+	values := map[string]string{
+		"A/EUR/2022-04-15": `{"Rates":{"table":"A","currency":"euro","code":"EUR","rates":[{"no":"074/A/NBP/2022","effectiveDate":"2022-04-15","mid":4.6378}]}}`,
+		"A/EUR/2022-04-16": `{}`,
+		"C/USD/2022-04-15": `{"Rates":{"table":"C","currency":"dolar amerykański","code":"USD","rates":[{"no":"074/C/NBP/2022","tradingDate":"2022-04-14","effectiveDate":"2022-04-15","bid":4.2455,"ask":4.3313}]}}`,
+	}
+	for key, value := range values {
+		if err := files.Set(key, []byte(value)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := boltcache.Open(filepath.Join(base, "nbp.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v, want no error", err)
+	}
+	defer b.Close()
+
+	if err := b.ImportFiles(filepath.Join(base, "files")); err != nil {
+		t.Fatalf("ImportFiles() error = %v, want no error", err)
+	}
+	for key, want := range values {
+		got, ok, err := b.Get(key)
+		if err != nil || !ok {
+			t.Errorf("Get(%s) = %q, %v, %v, want a hit", key, got, ok, err)
+			continue
+		}
+		if diff := cmp.Diff(want, string(got)); diff != "" {
+			t.Errorf("Get(%s) mismatch (-want +got):\n%s", key, diff)
+		}
+	}
+}
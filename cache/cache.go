@@ -0,0 +1,13 @@
+// Package cache provides storage backends for the gonbp exchange rates cache
+//
+// The backends store opaque values under slash-separated keys, e.g. "A/EUR/2022-04-15". All of them are safe for
+// concurrent use.
+package cache
+
+// Cache is a key-value store for cached NBP API responses
+type Cache interface {
+	// Get returns the value stored under key, or ok false if there is no such value
+	Get(key string) (value []byte, ok bool, err error)
+	// Set stores the value under key, replacing the previous value
+	Set(key string, value []byte) error
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testGetSet(t *testing.T, c Cache) {
+	t.Run("read non-existing value", func(t *testing.T) {
+		got, ok, err := c.Get("A/EUR/2022-04-15")
+		if err != nil || ok || got != nil {
+			t.Errorf("Get() = %q, %v, %v, want a miss", got, ok, err)
+		}
+	})
+
+	t.Run("set a value", func(t *testing.T) {
+		if err := c.Set("A/EUR/2022-04-15", []byte(`{"Rates":null}`)); err != nil {
+			t.Errorf("Set() error = %v, want no error", err)
+		}
+	})
+
+	t.Run("read value previously set", func(t *testing.T) {
+		got, ok, err := c.Get("A/EUR/2022-04-15")
+		if err != nil || !ok {
+			t.Errorf("Get() = %q, %v, %v, want a hit", got, ok, err)
+			return
+		}
+		if diff := cmp.Diff(`{"Rates":null}`, string(got)); diff != "" {
+			t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("overwrite a value", func(t *testing.T) {
+		if err := c.Set("A/EUR/2022-04-15", []byte(`{}`)); err != nil {
+			t.Errorf("Set() error = %v, want no error", err)
+			return
+		}
+		got, ok, err := c.Get("A/EUR/2022-04-15")
+		if err != nil || !ok {
+			t.Errorf("Get() = %q, %v, %v, want a hit", got, ok, err)
+			return
+		}
+		if diff := cmp.Diff(`{}`, string(got)); diff != "" {
+			t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("read different value", func(t *testing.T) {
+		got, ok, err := c.Get("C/EUR/2022-04-15")
+		if err != nil || ok || got != nil {
+			t.Errorf("Get() = %q, %v, %v, want a miss", got, ok, err)
+		}
+	})
+}
+
+func TestFile(t *testing.T) {
+	base, err := os.MkdirTemp("", "gonbp-TestFile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(base)
+
+	testGetSet(t, NewFile(base))
+
+	t.Run("one file per key", func(t *testing.T) {
+		if _, err := os.Stat(filepath.Join(base, "A", "EUR", "2022-04-15.json")); err != nil {
+			t.Errorf("Expected the value file to exist, got %v", err)
+		}
+	})
+}
+
+func TestMemory(t *testing.T) {
+	testGetSet(t, NewMemory(10))
+}
+
+func TestNop(t *testing.T) {
+	c := Nop{}
+	if err := c.Set("A/EUR/2022-04-15", []byte(`{}`)); err != nil {
+		t.Errorf("Set() error = %v, want no error", err)
+	}
+	got, ok, err := c.Get("A/EUR/2022-04-15")
+	if err != nil || ok || got != nil {
+		t.Errorf("Get() = %q, %v, %v, want a miss", got, ok, err)
+	}
+}
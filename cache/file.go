@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// File stores each value in its own JSON file under a base directory
+//
+// Key "A/EUR/2022-04-15" is stored in file "A/EUR/2022-04-15.json". The values are written to a temporary file first
+// and renamed into place, so that the readers never see a partially written file. Writers hold an advisory lock on
+// the ".lock" file in the base directory, which makes File safe to share between processes. A file which doesn't
+// contain valid JSON is considered corrupt: Get renames it to "<key>.json.corrupt" and reports a miss.
+type File struct {
+	dir string
+}
+
+// NewFile returns *File cache storing the values under dir
+func NewFile(dir string) *File {
+	return &File{dir: dir}
+}
+
+func (f *File) path(key string) string {
+	return filepath.Join(f.dir, filepath.FromSlash(key)+".json")
+}
+
+// Get returns the content of the file for a given key
+func (f *File) Get(key string) ([]byte, bool, error) {
+	p := f.path(key)
+	buf, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if json.Valid(buf) {
+		return buf, true, nil
+	}
+	return nil, false, f.quarantine(p)
+}
+
+// quarantine moves a corrupt file out of the way, unless a concurrent writer has already replaced it
+func (f *File) quarantine(p string) error {
+	unlock, err := f.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	buf, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if json.Valid(buf) {
+		return nil
+	}
+	return os.Rename(p, p+".corrupt")
+}
+
+// Set writes the value to the file for a given key, creating the directories if needed
+func (f *File) Set(key string, value []byte) error {
+	unlock, err := f.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	p := f.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), filepath.Base(p)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(value); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// lock takes the advisory lock on the cache directory and returns the function releasing it
+func (f *File) lock() (func(), error) {
+	if err := os.MkdirAll(f.dir, 0755); err != nil {
+		return nil, err
+	}
+	lf, err := os.OpenFile(filepath.Join(f.dir, ".lock"), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(lf); err != nil {
+		lf.Close()
+		return nil, err
+	}
+	return func() {
+		unlockFile(lf)
+		lf.Close()
+	}, nil
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestFile_Corrupt(t *testing.T) {
+	base, err := os.MkdirTemp("", "gonbp-TestFile_Corrupt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(base)
+
+	c := NewFile(base)
+	p := filepath.Join(base, "A", "EUR", "2022-04-15.json")
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(`{"Rates":{"table":"A","curr`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("corrupt file is a miss", func(t *testing.T) {
+		got, ok, err := c.Get("A/EUR/2022-04-15")
+		if err != nil || ok || got != nil {
+			t.Errorf("Get() = %q, %v, %v, want a miss", got, ok, err)
+		}
+	})
+
+	t.Run("corrupt file is quarantined", func(t *testing.T) {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("Expected the corrupt file to be moved, got %v", err)
+		}
+		if _, err := os.Stat(p + ".corrupt"); err != nil {
+			t.Errorf("Expected the quarantined file to exist, got %v", err)
+		}
+	})
+
+	t.Run("value can be set again", func(t *testing.T) {
+		if err := c.Set("A/EUR/2022-04-15", []byte(`{}`)); err != nil {
+			t.Errorf("Set() error = %v, want no error", err)
+			return
+		}
+		if _, ok, err := c.Get("A/EUR/2022-04-15"); err != nil || !ok {
+			t.Errorf("Get() = %v, %v, want a hit", ok, err)
+		}
+	})
+}
+
+func TestFile_ConcurrentSet(t *testing.T) {
+	base, err := os.MkdirTemp("", "gonbp-TestFile_ConcurrentSet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(base)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Separate instances, as if in separate processes
+			if err := NewFile(base).Set("A/EUR/2022-04-15", []byte(fmt.Sprintf(`{"writer":%d}`, i))); err != nil {
+				t.Errorf("Set() error = %v, want no error", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if _, ok, err := NewFile(base).Get("A/EUR/2022-04-15"); err != nil || !ok {
+		t.Errorf("Get() = %v, %v, want a hit", ok, err)
+	}
+	entries, err := os.ReadDir(filepath.Join(base, "A", "EUR"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected only the value file, got %v", entries)
+	}
+}
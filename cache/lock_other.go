@@ -0,0 +1,16 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+
+package cache
+
+import (
+	"os"
+)
+
+// lockFile is a no-op on platforms without advisory file locks, the writes are still atomic
+func lockFile(*os.File) error {
+	return nil
+}
+
+func unlockFile(*os.File) error {
+	return nil
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Memory is an in-memory cache which evicts the least recently used values
+type Memory struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type memoryEntry struct {
+	key   string
+	value []byte
+}
+
+// NewMemory returns *Memory cache holding at most size values
+//
+// Sizes lower than 1 are treated as 1, so the cache always holds the most recently set value.
+func NewMemory(size int) *Memory {
+	if size < 1 {
+		size = 1
+	}
+	return &Memory{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Get returns the value for a given key and marks it as recently used
+func (m *Memory) Get(key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	m.lru.MoveToFront(e)
+	return append([]byte(nil), e.Value.(*memoryEntry).value...), true, nil
+}
+
+// Set stores the value for a given key, evicting the least recently used value if the cache is full
+func (m *Memory) Set(key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value = append([]byte(nil), value...)
+	if e, ok := m.entries[key]; ok {
+		e.Value.(*memoryEntry).value = value
+		m.lru.MoveToFront(e)
+		return nil
+	}
+	m.entries[key] = m.lru.PushFront(&memoryEntry{key: key, value: value})
+	for m.lru.Len() > m.size {
+		oldest := m.lru.Back()
+		m.lru.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
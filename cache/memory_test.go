@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"testing"
+)
+
+func TestMemory_Evict(t *testing.T) {
+	c := NewMemory(2)
+	for _, key := range []string{"A/EUR/2022-04-13", "A/EUR/2022-04-14"} {
+		if err := c.Set(key, []byte(key)); err != nil {
+			t.Fatalf("Set() error = %v, want no error", err)
+		}
+	}
+	// Mark 2022-04-13 as recently used, so that 2022-04-14 is evicted first
+	if _, ok, _ := c.Get("A/EUR/2022-04-13"); !ok {
+		t.Fatalf("Get() miss, want a hit")
+	}
+	if err := c.Set("A/EUR/2022-04-15", []byte("A/EUR/2022-04-15")); err != nil {
+		t.Fatalf("Set() error = %v, want no error", err)
+	}
+
+	tests := []struct {
+		key    string
+		wantOk bool
+	}{
+		{key: "A/EUR/2022-04-13", wantOk: true},
+		{key: "A/EUR/2022-04-14", wantOk: false},
+		{key: "A/EUR/2022-04-15", wantOk: true},
+	}
+	for _, tt := range tests {
+		if _, ok, _ := c.Get(tt.key); ok != tt.wantOk {
+			t.Errorf("Get(%s) ok = %v, want %v", tt.key, ok, tt.wantOk)
+		}
+	}
+}
+
+func TestMemory_NonPositiveSize(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		c := NewMemory(size)
+		for _, key := range []string{"A/EUR/2022-04-14", "A/EUR/2022-04-15"} {
+			if err := c.Set(key, []byte(key)); err != nil {
+				t.Fatalf("NewMemory(%d).Set() error = %v, want no error", size, err)
+			}
+		}
+		if _, ok, _ := c.Get("A/EUR/2022-04-14"); ok {
+			t.Errorf("NewMemory(%d).Get() hit for the evicted value, want a miss", size)
+		}
+		if _, ok, _ := c.Get("A/EUR/2022-04-15"); !ok {
+			t.Errorf("NewMemory(%d).Get() miss for the most recent value, want a hit", size)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Package calendar knows the Polish public holidays and the business days on which NBP publishes the rates
+//
+// Only the civil date of a given time.Time is taken into account, the returned days are midnight UTC.
+package calendar
+
+import (
+	"time"
+)
+
+// Easter returns the date of Easter Sunday in a given year of the Gregorian calendar
+func Easter(year int) time.Time {
+	// Anonymous Gregorian algorithm (Meeus/Jones/Butcher)
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// Holiday is a Polish public holiday
+type Holiday struct {
+	Day time.Time
+	// Name is the Polish name of the holiday
+	Name string
+}
+
+// Holidays returns the Polish public holidays in a given year, ordered by day
+//
+// The list follows the current act on public holidays (ustawa o dniach wolnych od pracy) and its amendments:
+// Epiphany since 2011, Christmas Eve since 2025, and the one-off holiday on 12 November 2018.
+func Holidays(year int) []Holiday {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	easter := Easter(year)
+
+	holidays := []Holiday{{Day: date(time.January, 1), Name: "Nowy Rok"}}
+	if year >= 2011 {
+		holidays = append(holidays, Holiday{Day: date(time.January, 6), Name: "Święto Trzech Króli"})
+	}
+	holidays = append(holidays,
+		Holiday{Day: easter, Name: "Wielkanoc"},
+		Holiday{Day: easter.AddDate(0, 0, 1), Name: "Poniedziałek Wielkanocny"},
+		Holiday{Day: date(time.May, 1), Name: "Święto Pracy"},
+		Holiday{Day: date(time.May, 3), Name: "Święto Konstytucji 3 Maja"},
+		Holiday{Day: easter.AddDate(0, 0, 49), Name: "Zielone Świątki"},
+		Holiday{Day: easter.AddDate(0, 0, 60), Name: "Boże Ciało"},
+		Holiday{Day: date(time.August, 15), Name: "Wniebowzięcie Najświętszej Maryi Panny"},
+		Holiday{Day: date(time.November, 1), Name: "Wszystkich Świętych"},
+		Holiday{Day: date(time.November, 11), Name: "Narodowe Święto Niepodległości"},
+	)
+	if year == 2018 {
+		holidays = append(holidays, Holiday{Day: date(time.November, 12), Name: "Święto Niepodległości (100-lecie)"})
+	}
+	if year >= 2025 {
+		holidays = append(holidays, Holiday{Day: date(time.December, 24), Name: "Wigilia Bożego Narodzenia"})
+	}
+	holidays = append(holidays,
+		Holiday{Day: date(time.December, 25), Name: "Boże Narodzenie"},
+		Holiday{Day: date(time.December, 26), Name: "Drugi dzień Bożego Narodzenia"},
+	)
+	return holidays
+}
+
+// IsHoliday reports whether a given day is a Polish public holiday
+func IsHoliday(day time.Time) bool {
+	d := civil(day)
+	for _, h := range Holidays(d.Year()) {
+		if h.Day.Equal(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBusinessDay reports whether a given day is a business day, neither a weekend nor a public holiday
+//
+// NBP doesn't publish the rates on the days which are not business days.
+func IsBusinessDay(day time.Time) bool {
+	switch day.Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+	return !IsHoliday(day)
+}
+
+// PrevBusinessDay returns the last business day before a given day
+func PrevBusinessDay(day time.Time) time.Time {
+	return step(day, -1)
+}
+
+// NextBusinessDay returns the first business day after a given day
+func NextBusinessDay(day time.Time) time.Time {
+	return step(day, 1)
+}
+
+func step(day time.Time, step int) time.Time {
+	d := civil(day)
+	for {
+		d = d.AddDate(0, 0, step)
+		if IsBusinessDay(d) {
+			return d
+		}
+	}
+}
+
+// civil returns the civil date of a given time as midnight UTC
+func civil(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
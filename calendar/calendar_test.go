@@ -0,0 +1,107 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestEaster(t *testing.T) {
+	tests := []struct {
+		year int
+		want time.Time
+	}{
+		{year: 2011, want: day(2011, 4, 24)},
+		{year: 2019, want: day(2019, 4, 21)},
+		{year: 2022, want: day(2022, 4, 17)},
+		{year: 2024, want: day(2024, 3, 31)},
+		{year: 2025, want: day(2025, 4, 20)},
+		{year: 2038, want: day(2038, 4, 25)},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.want.Format("2006"), func(t *testing.T) {
+			if got := Easter(tt.year); !got.Equal(tt.want) {
+				t.Errorf("Easter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsBusinessDay(t *testing.T) {
+	tests := []struct {
+		name string
+		day  time.Time
+		want bool
+	}{
+		{name: "Regular Friday", day: day(2022, 4, 15), want: true},
+		{name: "Saturday", day: day(2022, 4, 16), want: false},
+		{name: "Sunday", day: day(2022, 4, 17), want: false},
+		{name: "Easter Monday", day: day(2022, 4, 18), want: false},
+		{name: "Corpus Christi", day: day(2022, 6, 16), want: false},
+		{name: "Constitution Day", day: day(2022, 5, 3), want: false},
+		{name: "Independence Day", day: day(2022, 11, 11), want: false},
+		{name: "Independence centenary", day: day(2018, 11, 12), want: false},
+		{name: "Epiphany", day: day(2022, 1, 6), want: false},
+		{name: "Epiphany before 2011", day: day(2010, 1, 6), want: true},
+		{name: "Christmas Eve before 2025", day: day(2024, 12, 24), want: true},
+		{name: "Christmas Eve since 2025", day: day(2025, 12, 24), want: false},
+		{name: "New Year's Eve", day: day(2025, 12, 31), want: true},
+		{name: "Time of day and zone are ignored", day: time.Date(2022, 4, 18, 23, 30, 0, 0, time.FixedZone("CEST", 2*60*60)), want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBusinessDay(tt.day); got != tt.want {
+				t.Errorf("IsBusinessDay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrevBusinessDay(t *testing.T) {
+	tests := []struct {
+		name string
+		day  time.Time
+		want time.Time
+	}{
+		{name: "Day before", day: day(2022, 4, 14), want: day(2022, 4, 13)},
+		{name: "Over Easter", day: day(2022, 4, 19), want: day(2022, 4, 15)},
+		{name: "Over Christmas 2025", day: day(2025, 12, 29), want: day(2025, 12, 23)},
+		{name: "Over New Year", day: day(2022, 1, 3), want: day(2021, 12, 31)},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, PrevBusinessDay(tt.day)); diff != "" {
+				t.Errorf("PrevBusinessDay() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNextBusinessDay(t *testing.T) {
+	tests := []struct {
+		name string
+		day  time.Time
+		want time.Time
+	}{
+		{name: "Day after", day: day(2022, 4, 13), want: day(2022, 4, 14)},
+		{name: "Over Easter", day: day(2022, 4, 15), want: day(2022, 4, 19)},
+		{name: "Over May holidays", day: day(2022, 4, 29), want: day(2022, 5, 2)},
+		{name: "Over Christmas 2025", day: day(2025, 12, 23), want: day(2025, 12, 29)},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, NextBusinessDay(tt.day)); diff != "" {
+				t.Errorf("NextBusinessDay() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/igor-kupczynski/gonbp"
+	"github.com/shopspring/decimal"
+)
+
+// Output formats of the nbp command
+const (
+	formatPlain = "plain"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+	formatTSV   = "tsv"
+)
+
+// row is a single record printed by the nbp command
+type row interface {
+	// header returns the column names, also used as the JSON keys
+	header() []string
+	// fields returns the values in the order of header
+	fields() []string
+	// labels returns the labels of the fields in the plain format
+	//
+	// The fields with an empty label are printed only if they differ between the rows, e.g. the currency of the rates
+	// of multiple currencies.
+	labels() []string
+}
+
+// rateRow is a mid rate of a currency, the template is executed with it
+type rateRow struct {
+	Currency gonbp.Currency
+	gonbp.Rate
+}
+
+func (r rateRow) header() []string {
+	return []string{"currency", "table_no", "day", "mid"}
+}
+
+func (r rateRow) fields() []string {
+	return []string{string(r.Currency), r.TableNo, r.Day.String(), r.Mid.String()}
+}
+
+func (r rateRow) labels() []string {
+	return []string{"", "Table No", "Day", "Rate"}
+}
+
+func (r rateRow) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Currency gonbp.Currency `json:"currency"`
+		TableNo  string         `json:"table_no"`
+		Day      gonbp.Date     `json:"day"`
+		Mid      json.Number    `json:"mid"`
+	}{r.Currency, r.TableNo, r.Day, number(r.Mid)})
+}
+
+// bidAskRow is a buy and sell rate of a currency from table C, the template is executed with it
+type bidAskRow struct {
+	Currency gonbp.Currency
+	gonbp.BidAskRate
+}
+
+func (r bidAskRow) header() []string {
+	return []string{"currency", "table_no", "trading_day", "day", "bid", "ask"}
+}
+
+func (r bidAskRow) fields() []string {
+	return []string{string(r.Currency), r.TableNo, r.TradingDay.String(), r.Day.String(), r.Bid.String(), r.Ask.String()}
+}
+
+func (r bidAskRow) labels() []string {
+	return []string{"", "Table No", "Trading Day", "Day", "Bid", "Ask"}
+}
+
+func (r bidAskRow) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Currency   gonbp.Currency `json:"currency"`
+		TableNo    string         `json:"table_no"`
+		TradingDay gonbp.Date     `json:"trading_day"`
+		Day        gonbp.Date     `json:"day"`
+		Bid        json.Number    `json:"bid"`
+		Ask        json.Number    `json:"ask"`
+	}{r.Currency, r.TableNo, r.TradingDay, r.Day, number(r.Bid), number(r.Ask)})
+}
+
+// currencyRow is a currency from the NBP catalog, the template is executed with it
+type currencyRow struct {
+	gonbp.CurrencyInfo
+}
+
+func (r currencyRow) header() []string {
+	return []string{"code", "table", "name"}
+}
+
+func (r currencyRow) fields() []string {
+	return []string{string(r.Code), string(r.Table), r.Name}
+}
+
+func (r currencyRow) labels() []string {
+	return []string{"Code", "Table", "Name"}
+}
+
+func (r currencyRow) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Code  gonbp.Currency `json:"code"`
+		Table gonbp.Table    `json:"table"`
+		Name  string         `json:"name"`
+	}{r.Code, r.Table, r.Name})
+}
+
+// number keeps the exact decimal value in JSON, without the quotes
+func number(d decimal.Decimal) json.Number {
+	return json.Number(d.String())
+}
+
+// printer writes the rows in the selected output format
+type printer struct {
+	w      io.Writer
+	format string
+	tmpl   *template.Template
+}
+
+// newPrinter returns a printer for a given format, or for a given text/template if tmpl is not empty
+func newPrinter(w io.Writer, format, tmpl string) (*printer, error) {
+	p := &printer{w: w, format: strings.ToLower(format)}
+	if tmpl != "" {
+		if p.format != formatPlain {
+			return nil, fmt.Errorf("template can't be combined with the %s format", p.format)
+		}
+		t, err := template.New("row").Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse template: %w", err)
+		}
+		p.tmpl = t
+		return p, nil
+	}
+	switch p.format {
+	case formatPlain, formatJSON, formatCSV, formatTSV:
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, must be one of plain, json, csv or tsv", format)
+	}
+}
+
+// print writes the rows, the csv and tsv formats start with a header row
+//
+// The kind is the zero value of the expected row type, the header is taken from it if there are no rows.
+func (p *printer) print(kind row, rows []row) error {
+	if p.tmpl != nil {
+		return p.printTemplate(rows)
+	}
+	switch p.format {
+	case formatJSON:
+		return p.printJSON(rows)
+	case formatCSV:
+		return p.printDelimited(kind, rows, ',')
+	case formatTSV:
+		return p.printDelimited(kind, rows, '\t')
+	default:
+		return p.printPlain(rows)
+	}
+}
+
+// printTemplate executes the template for every row, each followed by a newline
+func (p *printer) printTemplate(rows []row) error {
+	for _, r := range rows {
+		if err := p.tmpl.Execute(p.w, r); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(p.w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printJSON writes the rows as a JSON array
+func (p *printer) printJSON(rows []row) error {
+	if rows == nil {
+		rows = []row{}
+	}
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
+
+// printDelimited writes the header row followed by the rows, the header is written even if there are no rows
+func (p *printer) printDelimited(kind row, rows []row, comma rune) error {
+	header := kind.header()
+	if len(rows) > 0 {
+		header = rows[0].header()
+	}
+	w := csv.NewWriter(p.w)
+	w.Comma = comma
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := w.Write(r.fields()); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// printPlain writes a single row as aligned "label: value" lines, and multiple rows as one line per row
+func (p *printer) printPlain(rows []row) error {
+	if len(rows) == 1 {
+		labels, fields := rows[0].labels(), rows[0].fields()
+		width := 0
+		for _, label := range labels {
+			if len(label) > width {
+				width = len(label)
+			}
+		}
+		for i, label := range labels {
+			if label == "" {
+				continue
+			}
+			if _, err := fmt.Fprintf(p.w, "%*s: %s\n", width, label, fields[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, r := range rows {
+		var line []string
+		for i, label := range r.labels() {
+			if label != "" || varies(rows, i) {
+				line = append(line, r.fields()[i])
+			}
+		}
+		if _, err := fmt.Fprintln(p.w, strings.Join(line, "  ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// varies reports whether the i-th field differs between the rows
+func varies(rows []row, i int) bool {
+	for _, r := range rows[1:] {
+		if r.fields()[i] != rows[0].fields()[i] {
+			return true
+		}
+	}
+	return false
+}
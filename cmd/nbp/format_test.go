@@ -0,0 +1,133 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/igor-kupczynski/gonbp"
+	"github.com/shopspring/decimal"
+)
+
+func TestPrinter_Print(t *testing.T) {
+	eur := rateRow{Currency: gonbp.EUR, Rate: gonbp.Rate{
+		TableNo: "074/A/NBP/2022", Day: gonbp.NewDate(2022, time.April, 15), Mid: decimal.RequireFromString("4.6378"),
+	}}
+	eur14 := rateRow{Currency: gonbp.EUR, Rate: gonbp.Rate{
+		TableNo: "073/A/NBP/2022", Day: gonbp.NewDate(2022, time.April, 14), Mid: decimal.RequireFromString("4.6215"),
+	}}
+	usd := rateRow{Currency: gonbp.USD, Rate: gonbp.Rate{
+		TableNo: "074/A/NBP/2022", Day: gonbp.NewDate(2022, time.April, 15), Mid: decimal.RequireFromString("4.2865"),
+	}}
+	tests := []struct {
+		name     string
+		format   string
+		template string
+		kind     row
+		rows     []row
+		want     string
+	}{
+		{
+			name:   "Plain single rate",
+			format: formatPlain,
+			rows:   []row{eur},
+			want:   "Table No: 074/A/NBP/2022\n     Day: 2022-04-15\n    Rate: 4.6378\n",
+		},
+		{
+			name:   "Plain multiple rates",
+			format: formatPlain,
+			rows:   []row{eur14, eur},
+			want:   "073/A/NBP/2022  2022-04-14  4.6215\n074/A/NBP/2022  2022-04-15  4.6378\n",
+		},
+		{
+			name:   "Plain multiple currencies",
+			format: formatPlain,
+			rows:   []row{eur14, eur, usd},
+			want: "EUR  073/A/NBP/2022  2022-04-14  4.6215\n" +
+				"EUR  074/A/NBP/2022  2022-04-15  4.6378\n" +
+				"USD  074/A/NBP/2022  2022-04-15  4.2865\n",
+		},
+		{
+			name:   "JSON",
+			format: formatJSON,
+			rows:   []row{eur},
+			want:   "[\n  {\n    \"currency\": \"EUR\",\n    \"table_no\": \"074/A/NBP/2022\",\n    \"day\": \"2022-04-15\",\n    \"mid\": 4.6378\n  }\n]\n",
+		},
+		{
+			name:   "JSON without rows",
+			format: formatJSON,
+			want:   "[]\n",
+		},
+		{
+			name:   "CSV",
+			format: "CSV",
+			rows:   []row{eur, usd},
+			want:   "currency,table_no,day,mid\nEUR,074/A/NBP/2022,2022-04-15,4.6378\nUSD,074/A/NBP/2022,2022-04-15,4.2865\n",
+		},
+		{
+			name:   "CSV without rows",
+			format: formatCSV,
+			want:   "currency,table_no,day,mid\n",
+		},
+		{
+			name:   "TSV without bid/ask rows",
+			format: formatTSV,
+			kind:   bidAskRow{},
+			want:   "currency\ttable_no\ttrading_day\tday\tbid\task\n",
+		},
+		{
+			name:   "TSV",
+			format: formatTSV,
+			rows:   []row{eur},
+			want:   "currency\ttable_no\tday\tmid\nEUR\t074/A/NBP/2022\t2022-04-15\t4.6378\n",
+		},
+		{
+			name:     "Template",
+			format:   formatPlain,
+			template: "{{.Currency}} {{.Day}} {{.Mid}}",
+			rows:     []row{eur, usd},
+			want:     "EUR 2022-04-15 4.6378\nUSD 2022-04-15 4.2865\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			p, err := newPrinter(&out, tt.format, tt.template)
+			if err != nil {
+				t.Fatalf("newPrinter() error = %v, want no error", err)
+			}
+			kind := tt.kind
+			if kind == nil {
+				kind = rateRow{}
+			}
+			if err := p.print(kind, tt.rows); err != nil {
+				t.Fatalf("print() error = %v, want no error", err)
+			}
+			if diff := cmp.Diff(tt.want, out.String()); diff != "" {
+				t.Errorf("print() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewPrinter(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		template string
+	}{
+		{name: "Unknown format", format: "xml"},
+		{name: "Template with a machine format", format: formatJSON, template: "{{.Mid}}"},
+		{name: "Invalid template", format: formatPlain, template: "{{.Mid"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newPrinter(&strings.Builder{}, tt.format, tt.template); err == nil {
+				t.Errorf("newPrinter() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -5,27 +5,50 @@ import (
 	"fmt"
 	"github.com/igor-kupczynski/gonbp"
 	"log"
+	"os"
 	"strings"
-	"time"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "tax" {
+		taxMain(os.Args[2:])
+		return
+	}
+
 	previous := flag.Bool("p", false, "fetch rate for the previous work day")
+	table := flag.String("t", "A", "NBP table to fetch the rate from: A, B or C; defaults to the table listing the currency")
+	last := flag.Int("n", 0, "fetch the last N published rates")
+	list := flag.Bool("l", false, "list the currencies from NBP tables A and B")
+	format := flag.String("format", formatPlain, "output format: plain, json, csv or tsv")
+	tmpl := flag.String("template", "", "print every rate with a given Go text/template, e.g. '{{.Day}} {{.Mid}}'")
 	flag.Parse()
 	args := flag.Args()
 
-	if len(args) <= 0 {
-		log.Fatalf("Currency is required, e.g. nbp eur")
+	out, err := newPrinter(os.Stdout, *format, *tmpl)
+	if err != nil {
+		log.Fatalf("Can't print rates: %v", err)
 	}
-	curr := gonbp.Currency(strings.ToUpper(args[0]))
 
-	var date time.Time = time.Now()
-	var err error
-	if len(args) > 1 {
-		date, err = time.Parse("2006-01-02", args[1])
-		if err != nil {
-			log.Fatalf("Can't parse date: %v", err)
-		}
+	if *list {
+		listCurrencies(out)
+		return
+	}
+
+	currencies, spans, err := parseArgs(args)
+	if err != nil {
+		log.Fatalf("Can't parse arguments: %v", err)
+	}
+	if len(currencies) == 0 {
+		log.Fatalf("Currency is required, e.g. nbp eur")
+	}
+	dated := len(spans) > 0
+	if !dated {
+		today := gonbp.Today()
+		spans = []span{{from: today, to: today}}
+	}
+	ranged := false
+	for _, s := range spans {
+		ranged = ranged || s.isRange
 	}
 
 	nbp, err := gonbp.Default()
@@ -33,18 +56,134 @@ func main() {
 		log.Fatalf("Can't create nbp client: %v", err)
 	}
 
+	tableSet := false
+	flag.Visit(func(f *flag.Flag) {
+		tableSet = tableSet || f.Name == "t"
+	})
+	if *last > 0 && (*previous || dated) {
+		log.Fatalf("Last N rates can't be combined with a date or a previous work day")
+	}
+	if *previous && ranged {
+		log.Fatalf("Previous work day can't be combined with a date range")
+	}
+
+	var jobs []job
+	var kind row = rateRow{}
+	for i, curr := range currencies {
+		table := gonbp.Table(strings.ToUpper(*table))
+		if !tableSet {
+			// The catalog only picks the default table. If it can't be fetched, e.g. offline, or doesn't list the
+			// currency, e.g. a withdrawn one, the rate is fetched from table A, which reports the actual error.
+			if info, err := nbp.LookupCurrency(curr); err == nil {
+				table = info.Table
+			}
+		}
+
+		if table == gonbp.TableC {
+			if i == 0 {
+				kind = bidAskRow{}
+			}
+			if *previous {
+				log.Fatalf("Previous work day is not supported for table C")
+			}
+			if *last > 0 {
+				log.Fatalf("Last N rates are not supported for table C")
+			}
+			if ranged {
+				log.Fatalf("Date ranges are not supported for table C")
+			}
+		}
+		jobs = append(jobs, currencyJobs(nbp.WithTable(table), table, curr, spans, *previous, *last)...)
+	}
+
+	rows, err := fetchAll(jobs)
+	if err != nil {
+		log.Fatalf("Can't fetch rates: %v", err)
+	}
+	printRows(out, kind, rows)
+}
+
+// currencyJobs returns the jobs fetching the rates of a given currency for every span, or the last N rates
+func currencyJobs(nbp *gonbp.NBP, table gonbp.Table, curr gonbp.Currency, spans []span, previous bool, last int) []job {
+	if last > 0 {
+		return []job{func() ([]row, error) {
+			rates, err := nbp.LastRates(curr, last)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", curr, err)
+			}
+			return rateRows(curr, rates), nil
+		}}
+	}
+	jobs := make([]job, 0, len(spans))
+	for _, s := range spans {
+		s := s
+		jobs = append(jobs, func() ([]row, error) {
+			rows, err := fetchSpan(nbp, table, curr, s, previous)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", curr, s, err)
+			}
+			return rows, nil
+		})
+	}
+	return jobs
+}
+
+// fetchSpan returns the rates of a given currency for a single day, or every published day in a range of days
+func fetchSpan(nbp *gonbp.NBP, table gonbp.Table, curr gonbp.Currency, s span, previous bool) ([]row, error) {
+	switch {
+	case table == gonbp.TableC:
+		rate, err := nbp.BidAskRateOn(curr, s.from)
+		if err != nil {
+			return nil, err
+		}
+		return []row{bidAskRow{Currency: curr, BidAskRate: *rate}}, nil
+	case s.isRange:
+		rates, err := nbp.RatesBetween(curr, s.from, s.to)
+		if err != nil {
+			return nil, err
+		}
+		return rateRows(curr, rates), nil
+	}
 	var rate *gonbp.Rate
-	if *previous {
-		rate, err = nbp.PreviousRate(curr, date)
+	var err error
+	if previous {
+		rate, err = nbp.PreviousRateOn(curr, s.from)
 	} else {
-		rate, err = nbp.Rate(curr, date)
+		rate, err = nbp.RateOn(curr, s.from)
 	}
 	if err != nil {
-		log.Fatalf("Can't fetch rates: %v", err)
+		return nil, err
 	}
+	return []row{rateRow{Currency: curr, Rate: *rate}}, nil
+}
 
-	fmt.Printf("Table No: %s\n", rate.TableNo)
-	fmt.Printf("     Day: %s\n", rate.Day.Format("2006-01-02"))
-	fmt.Printf("    Rate: %s\n", rate.Mid)
+func rateRows(curr gonbp.Currency, rates []gonbp.Rate) []row {
+	rows := make([]row, 0, len(rates))
+	for _, rate := range rates {
+		rows = append(rows, rateRow{Currency: curr, Rate: rate})
+	}
+	return rows
+}
+
+// printRows writes the rows of a given kind to stdout, or exits if that fails
+func printRows(out *printer, kind row, rows []row) {
+	if err := out.print(kind, rows); err != nil {
+		log.Fatalf("Can't print rates: %v", err)
+	}
+}
 
+func listCurrencies(out *printer) {
+	nbp, err := gonbp.Default()
+	if err != nil {
+		log.Fatalf("Can't create nbp client: %v", err)
+	}
+	currencies, err := nbp.Currencies()
+	if err != nil {
+		log.Fatalf("Can't fetch the list of currencies: %v", err)
+	}
+	rows := make([]row, 0, len(currencies))
+	for _, c := range currencies {
+		rows = append(rows, currencyRow{CurrencyInfo: c})
+	}
+	printRows(out, currencyRow{}, rows)
 }
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/igor-kupczynski/gonbp"
+)
+
+// rangeSep separates the first and the last day of a date range, e.g. 2022-04-01..2022-04-30
+const rangeSep = ".."
+
+// span is a single day, or a range of days from the command line
+type span struct {
+	from, to gonbp.Date
+	isRange  bool
+}
+
+func (s span) String() string {
+	if s.isRange {
+		return s.from.String() + rangeSep + s.to.String()
+	}
+	return s.from.String()
+}
+
+// parseSpan parses a single day, e.g. 2022-04-15, or an inclusive range of days, e.g. 2022-04-01..2022-04-30
+func parseSpan(s string) (span, error) {
+	first, last, isRange := strings.Cut(s, rangeSep)
+	from, err := gonbp.ParseDate(first)
+	if err != nil {
+		return span{}, err
+	}
+	if !isRange {
+		return span{from: from, to: from}, nil
+	}
+	to, err := gonbp.ParseDate(last)
+	if err != nil {
+		return span{}, err
+	}
+	if to.Before(from) {
+		return span{}, fmt.Errorf("invalid range %s: %s is before %s", s, to, from)
+	}
+	return span{from: from, to: to, isRange: true}, nil
+}
+
+// parseArgs splits the command line arguments into the currencies and the days, in the order given
+//
+// The arguments starting with a digit are days or ranges of days, the others are currencies.
+func parseArgs(args []string) ([]gonbp.Currency, []span, error) {
+	var currencies []gonbp.Currency
+	var spans []span
+	for _, arg := range args {
+		if arg != "" && arg[0] >= '0' && arg[0] <= '9' {
+			s, err := parseSpan(arg)
+			if err != nil {
+				return nil, nil, err
+			}
+			spans = append(spans, s)
+			continue
+		}
+		curr, err := gonbp.ParseCurrency(arg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("can't parse currency: %w", err)
+		}
+		currencies = append(currencies, curr)
+	}
+	return currencies, spans, nil
+}
+
+// job fetches the rows for a single currency and span
+type job func() ([]row, error)
+
+// fetchAll runs the jobs concurrently and returns their rows in the order of the jobs
+//
+// The NBP client limits the concurrent NBP API calls and shares the cache between the jobs. If any of the jobs fails,
+// fetchAll returns the error of the first failed job.
+func fetchAll(jobs []job) ([]row, error) {
+	results := make([][]row, len(jobs))
+	errs := make([]error, len(jobs))
+	var wg sync.WaitGroup
+	for i, j := range jobs {
+		wg.Add(1)
+		go func(i int, j job) {
+			defer wg.Done()
+			results[i], errs[i] = j()
+		}(i, j)
+	}
+	wg.Wait()
+
+	var rows []row
+	for i := range jobs {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		rows = append(rows, results[i]...)
+	}
+	return rows, nil
+}
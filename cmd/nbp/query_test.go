@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/igor-kupczynski/gonbp"
+)
+
+func TestParseArgs(t *testing.T) {
+	apr := func(day int) gonbp.Date {
+		return gonbp.NewDate(2022, time.April, day)
+	}
+	tests := []struct {
+		name           string
+		args           []string
+		wantCurrencies []gonbp.Currency
+		wantSpans      []span
+		wantErr        bool
+	}{
+		{
+			name:           "Single currency",
+			args:           []string{"eur"},
+			wantCurrencies: []gonbp.Currency{gonbp.EUR},
+		},
+		{
+			name:           "Currencies, dates and a range",
+			args:           []string{"USD", "2022-04-14", "EUR", "2022-04-01..2022-04-30", "CHF"},
+			wantCurrencies: []gonbp.Currency{gonbp.USD, gonbp.EUR, gonbp.CHF},
+			wantSpans: []span{
+				{from: apr(14), to: apr(14)},
+				{from: apr(1), to: apr(30), isRange: true},
+			},
+		},
+		{
+			name:    "Invalid date",
+			args:    []string{"EUR", "2022-04-31"},
+			wantErr: true,
+		},
+		{
+			name:    "Inverted range",
+			args:    []string{"EUR", "2022-04-30..2022-04-01"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid currency",
+			args:    []string{"EURO"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			currencies, spans, err := parseArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseArgs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.wantCurrencies, currencies); diff != "" {
+				t.Errorf("parseArgs() currencies mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantSpans, spans, cmp.AllowUnexported(span{})); diff != "" {
+				t.Errorf("parseArgs() spans mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFetchAll(t *testing.T) {
+	rowsOf := func(codes ...gonbp.CurrencyInfo) job {
+		return func() ([]row, error) {
+			var rows []row
+			for _, c := range codes {
+				rows = append(rows, currencyRow{CurrencyInfo: c})
+			}
+			return rows, nil
+		}
+	}
+	eur := gonbp.CurrencyInfo{Code: gonbp.EUR, Name: "euro", Table: gonbp.TableA}
+	usd := gonbp.CurrencyInfo{Code: gonbp.USD, Name: "dolar amerykański", Table: gonbp.TableA}
+
+	got, err := fetchAll([]job{rowsOf(eur, usd), rowsOf(), rowsOf(usd)})
+	if err != nil {
+		t.Fatalf("fetchAll() error = %v, want no error", err)
+	}
+	want := []row{currencyRow{CurrencyInfo: eur}, currencyRow{CurrencyInfo: usd}, currencyRow{CurrencyInfo: usd}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("fetchAll() mismatch (-want +got):\n%s", diff)
+	}
+
+	first, second := errors.New("first"), errors.New("second")
+	failing := func(err error) job {
+		return func() ([]row, error) { return nil, err }
+	}
+	if _, err := fetchAll([]job{rowsOf(eur), failing(first), failing(second)}); err != first {
+		t.Errorf("fetchAll() error = %v, want %v", err, first)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/igor-kupczynski/gonbp"
+	"github.com/igor-kupczynski/gonbp/tax"
+	"github.com/igor-kupczynski/gonbp/tax/gains"
+)
+
+const taxUsage = `Usage: nbp tax <command> [arguments]
+
+Commands:
+  convert [ledger.csv]  convert a CSV ledger of transactions to PLN with the previous business day rates
+  gains [trades.csv]    match the trades FIFO and report the capital gains in PLN
+`
+
+// taxMain runs the nbp tax subcommands
+func taxMain(args []string) {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, taxUsage)
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "convert":
+		taxConvert(args[1:])
+	case "gains":
+		taxGains(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown tax command %q\n\n%s", args[0], taxUsage)
+		os.Exit(2)
+	}
+}
+
+func taxConvert(args []string) {
+	fs := flag.NewFlagSet("nbp tax convert", flag.ExitOnError)
+	output := fs.String("o", "", "write the converted ledger to a given file instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: nbp tax convert [-o output.csv] [ledger.csv]\n\n")
+		fmt.Fprintf(fs.Output(), "Reads the ledger from stdin if no file is given. The ledger needs date, currency, amount and kind columns.\n\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	in, out, done := taxFiles(fs, *output)
+	defer done()
+
+	nbp, err := gonbp.Default()
+	if err != nil {
+		log.Fatalf("Can't create nbp client: %v", err)
+	}
+	if err := tax.New(nbp).ConvertLedger(in, out); err != nil {
+		log.Fatalf("Can't convert ledger: %v", err)
+	}
+}
+
+func taxGains(args []string) {
+	fs := flag.NewFlagSet("nbp tax gains", flag.ExitOnError)
+	output := fs.String("o", "", "write the report to a given file instead of stdout")
+	yearly := fs.Bool("y", false, "report the yearly summary instead of the lots")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: nbp tax gains [-y] [-o output.csv] [trades.csv]\n\n")
+		fmt.Fprintf(fs.Output(), "Reads the trades from stdin if no file is given. The trades need date, instrument, side, quantity, price,\n")
+		fmt.Fprintf(fs.Output(), "commission and currency columns.\n\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	in, out, done := taxFiles(fs, *output)
+	defer done()
+
+	trades, err := gains.ReadTrades(in)
+	if err != nil {
+		log.Fatalf("Can't read trades: %v", err)
+	}
+	nbp, err := gonbp.Default()
+	if err != nil {
+		log.Fatalf("Can't create nbp client: %v", err)
+	}
+	report, err := gains.New(nbp).Calculate(trades)
+	if err != nil {
+		log.Fatalf("Can't calculate gains: %v", err)
+	}
+	if *yearly {
+		err = gains.WriteYears(out, report.Years)
+	} else {
+		err = gains.WriteLots(out, report.Lots)
+	}
+	if err != nil {
+		log.Fatalf("Can't write report: %v", err)
+	}
+}
+
+// taxFiles opens the input file given as the first argument or stdin, and the output file or stdout
+func taxFiles(fs *flag.FlagSet, output string) (io.Reader, io.Writer, func()) {
+	var closers []io.Closer
+	var in io.Reader = os.Stdin
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			log.Fatalf("Can't open input file: %v", err)
+		}
+		closers = append(closers, f)
+		in = f
+	}
+	var out io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			log.Fatalf("Can't create output file: %v", err)
+		}
+		closers = append(closers, f)
+		out = f
+	}
+	return in, out, func() {
+		for _, c := range closers {
+			c.Close()
+		}
+	}
+}
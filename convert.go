@@ -0,0 +1,125 @@
+package gonbp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RoundingMode enumerates the ways of rounding a converted amount to the minor units of the target currency
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds half away from zero, e.g. 1.005 to 1.01 and -1.005 to -1.01
+	RoundHalfUp RoundingMode = iota
+	// RoundBankers rounds half to even, e.g. 1.005 to 1.00 and 1.015 to 1.02
+	RoundBankers
+	// RoundTruncate drops the digits past the minor units, e.g. 1.009 to 1.00
+	RoundTruncate
+)
+
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundHalfUp:
+		return "half-up"
+	case RoundBankers:
+		return "bankers"
+	case RoundTruncate:
+		return "truncate"
+	default:
+		return fmt.Sprintf("RoundingMode(%d)", int(m))
+	}
+}
+
+// Round rounds d to a given number of decimal places
+func (m RoundingMode) Round(d decimal.Decimal, places int32) decimal.Decimal {
+	switch m {
+	case RoundBankers:
+		return d.RoundBank(places)
+	case RoundTruncate:
+		return d.Truncate(places)
+	default:
+		return d.Round(places)
+	}
+}
+
+// Conversion is the result of converting an amount between two currencies with the NBP mid rates
+type Conversion struct {
+	Amount decimal.Decimal
+	From   Currency
+	To     Currency
+	// FromRate is the rate used to convert From to PLN, nil if From is PLN
+	FromRate *Rate
+	// ToRate is the rate used to convert PLN to To, nil if To is PLN
+	ToRate *Rate
+	// Value is the unrounded converted amount, with at most 16 decimal places in a cross rate conversion
+	Value decimal.Decimal
+	// Rounded is Value rounded to the minor units of To
+	Rounded decimal.Decimal
+	// Rounding is the rounding mode used
+	Rounding RoundingMode
+}
+
+// WithRounding returns a copy of *NBP instance which rounds the converted amounts with a given mode
+//
+// The copy shares the cache and the http client with the original instance. The default mode is RoundHalfUp.
+func (n *NBP) WithRounding(mode RoundingMode) *NBP {
+	c := *n
+	c.rounding = mode
+	return &c
+}
+
+// Convert is like ConvertOn for the civil date of day
+func (n *NBP) Convert(amount decimal.Decimal, from, to Currency, day time.Time) (*Conversion, error) {
+	return n.ConvertOnContext(context.Background(), amount, from, to, DateOf(day))
+}
+
+// ConvertContext is like Convert, but the NBP API calls are bound to ctx
+func (n *NBP) ConvertContext(ctx context.Context, amount decimal.Decimal, from, to Currency, day time.Time) (*Conversion, error) {
+	return n.ConvertOnContext(ctx, amount, from, to, DateOf(day))
+}
+
+// ConvertOn converts an amount between two currencies with the mid rates for a given date
+//
+// The amount is converted via PLN, e.g. EUR to USD uses both the EUR and the USD rates. The rates come from NBP
+// table A, or the table selected with WithTable. The result is rounded to the minor units of the target currency
+// with RoundHalfUp, or the mode selected with WithRounding. Currencies without the minor units, e.g. XDR, are not
+// rounded.
+func (n *NBP) ConvertOn(amount decimal.Decimal, from, to Currency, day Date) (*Conversion, error) {
+	return n.ConvertOnContext(context.Background(), amount, from, to, day)
+}
+
+// ConvertOnContext is like ConvertOn, but the NBP API calls are bound to ctx
+func (n *NBP) ConvertOnContext(ctx context.Context, amount decimal.Decimal, from, to Currency, day Date) (*Conversion, error) {
+	for _, curr := range []Currency{from, to} {
+		if err := curr.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	c := &Conversion{Amount: amount, From: from, To: to, Value: amount, Rounding: n.rounding}
+	if from != to {
+		if from != PLN {
+			rate, err := n.RateOnContext(ctx, from, day)
+			if err != nil {
+				return nil, err
+			}
+			c.FromRate = rate
+			c.Value = c.Value.Mul(rate.Mid)
+		}
+		if to != PLN {
+			rate, err := n.RateOnContext(ctx, to, day)
+			if err != nil {
+				return nil, err
+			}
+			c.ToRate = rate
+			c.Value = c.Value.Div(rate.Mid)
+		}
+	}
+	c.Rounded = c.Value
+	if places := to.MinorUnits(); places >= 0 {
+		c.Rounded = n.rounding.Round(c.Value, int32(places))
+	}
+	return c, nil
+}
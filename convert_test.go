@@ -0,0 +1,158 @@
+package gonbp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/igor-kupczynski/gonbp/internal/nbpapi"
+	"github.com/shopspring/decimal"
+)
+
+func TestRoundingMode_Round(t *testing.T) {
+	tests := []struct {
+		d        string
+		halfUp   string
+		bankers  string
+		truncate string
+	}{
+		{d: "1.005", halfUp: "1.01", bankers: "1", truncate: "1"},
+		{d: "1.015", halfUp: "1.02", bankers: "1.02", truncate: "1.01"},
+		{d: "1.009", halfUp: "1.01", bankers: "1.01", truncate: "1"},
+		{d: "-1.005", halfUp: "-1.01", bankers: "-1", truncate: "-1"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.d, func(t *testing.T) {
+			d := decimal.RequireFromString(tt.d)
+			for mode, want := range map[RoundingMode]string{
+				RoundHalfUp:   tt.halfUp,
+				RoundBankers:  tt.bankers,
+				RoundTruncate: tt.truncate,
+			} {
+				if got := mode.Round(d, 2); got.String() != want {
+					t.Errorf("%s Round() = %s, want %s", mode, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNBP_Convert(t *testing.T) {
+	urls := map[string]mockResponse{
+		"A/EUR/2022-04-15": {rates: &nbpapi.Rates{Table: "A", Currency: "euro", Code: "EUR", Rates: []nbpapi.DailyRate{
+			{No: "074/A/NBP/2022", EffectiveDate: "2022-04-15", Mid: decimal.NewFromFloat(4.6378)},
+		}}},
+		"A/USD/2022-04-15": {rates: &nbpapi.Rates{Table: "A", Currency: "dolar amerykański", Code: "USD", Rates: []nbpapi.DailyRate{
+			{No: "074/A/NBP/2022", EffectiveDate: "2022-04-15", Mid: decimal.NewFromFloat(4.2865)},
+		}}},
+		"A/EUR/2022-04-16": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+	}
+	eur := &Rate{TableNo: "074/A/NBP/2022", Day: date(2022, 4, 15), Mid: decimal.NewFromFloat(4.6378)}
+	usd := &Rate{TableNo: "074/A/NBP/2022", Day: date(2022, 4, 15), Mid: decimal.NewFromFloat(4.2865)}
+	tests := []struct {
+		name     string
+		amount   string
+		from     Currency
+		to       Currency
+		day      time.Time
+		rounding RoundingMode
+		want     *Conversion
+		wantErr  error
+	}{
+		{
+			name:   "EUR to PLN",
+			amount: "100",
+			from:   EUR,
+			to:     PLN,
+			day:    day(2022, 4, 15),
+			want: &Conversion{
+				Amount:   decimal.RequireFromString("100"),
+				From:     EUR,
+				To:       PLN,
+				FromRate: eur,
+				Value:    decimal.RequireFromString("463.78"),
+				Rounded:  decimal.RequireFromString("463.78"),
+			},
+		},
+		{
+			name:   "PLN to USD",
+			amount: "1000",
+			from:   PLN,
+			to:     USD,
+			day:    day(2022, 4, 15),
+			want: &Conversion{
+				Amount:  decimal.RequireFromString("1000"),
+				From:    PLN,
+				To:      USD,
+				ToRate:  usd,
+				Value:   decimal.RequireFromString("233.2905633967106031"),
+				Rounded: decimal.RequireFromString("233.29"),
+			},
+		},
+		{
+			name:     "EUR to USD via PLN, truncated",
+			amount:   "100",
+			from:     EUR,
+			to:       USD,
+			day:      day(2022, 4, 15),
+			rounding: RoundTruncate,
+			want: &Conversion{
+				Amount:   decimal.RequireFromString("100"),
+				From:     EUR,
+				To:       USD,
+				FromRate: eur,
+				ToRate:   usd,
+				Value:    decimal.RequireFromString("108.1954974921264435"),
+				Rounded:  decimal.RequireFromString("108.19"),
+				Rounding: RoundTruncate,
+			},
+		},
+		{
+			name:     "Same currency, bankers rounding",
+			amount:   "2.345",
+			from:     PLN,
+			to:       PLN,
+			day:      day(2022, 4, 15),
+			rounding: RoundBankers,
+			want: &Conversion{
+				Amount:   decimal.RequireFromString("2.345"),
+				From:     PLN,
+				To:       PLN,
+				Value:    decimal.RequireFromString("2.345"),
+				Rounded:  decimal.RequireFromString("2.34"),
+				Rounding: RoundBankers,
+			},
+		},
+		{
+			name:    "No rate for a given day",
+			amount:  "100",
+			from:    EUR,
+			to:      PLN,
+			day:     day(2022, 4, 16),
+			wantErr: ErrNoExchangeRateForGivenDay,
+		},
+		{
+			name:    "Invalid currency",
+			amount:  "100",
+			from:    EUR,
+			to:      "DOGE",
+			day:     day(2022, 4, 15),
+			wantErr: ErrInvalidCurrency,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			n := testNBP(&mockClient{urls: urls}).WithRounding(tt.rounding)
+			got, err := n.Convert(decimal.RequireFromString(tt.amount), tt.from, tt.to, tt.day)
+			if diff := cmp.Diff(tt.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Convert() error mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Convert() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
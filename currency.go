@@ -0,0 +1,138 @@
+package gonbp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/igor-kupczynski/gonbp/internal/nbpapi"
+)
+
+// Currency is a three letter currency code, e.g. EUR
+//
+// Use ParseCurrency to get a Currency from user input, and NBP.Currencies for the list of currencies NBP publishes
+// the rates for.
+type Currency string
+
+const (
+	CHF Currency = "CHF"
+	EUR Currency = "EUR"
+	PLN Currency = "PLN"
+	USD Currency = "USD"
+)
+
+// ErrInvalidCurrency represents a failure where a currency code is not an ISO 4217 code
+var ErrInvalidCurrency = errors.New("invalid currency code")
+
+// ErrUnknownCurrency represents a failure where a currency is not listed in NBP tables A and B
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// ParseCurrency returns the Currency for a given ISO 4217 code, ignoring case and surrounding whitespace
+//
+// ParseCurrency doesn't call the NBP API. See NBP.LookupCurrency to check if NBP publishes the rates for the currency.
+func ParseCurrency(s string) (Currency, error) {
+	curr := Currency(strings.ToUpper(strings.TrimSpace(s)))
+	if err := curr.Validate(); err != nil {
+		return "", err
+	}
+	return curr, nil
+}
+
+// Validate returns ErrInvalidCurrency if c is not an ISO 4217 currency code
+//
+// The codes withdrawn from ISO 4217, for which NBP published the rates in the past, e.g. HRK, are valid.
+func (c Currency) Validate() error {
+	if _, ok := iso4217[c]; !ok {
+		return fmt.Errorf("%w: %q is not an ISO 4217 code", ErrInvalidCurrency, string(c))
+	}
+	return nil
+}
+
+// Numeric returns the ISO 4217 numeric code of the currency, e.g. "978" for EUR, or "" if c is not valid
+func (c Currency) Numeric() string {
+	return iso4217[c].numeric
+}
+
+// MinorUnits returns the number of digits after the decimal separator of the currency, e.g. 2 for EUR or 0 for JPY
+//
+// MinorUnits returns -1 if c is not valid, or if ISO 4217 doesn't define the minor units of the currency, e.g. XDR.
+func (c Currency) MinorUnits() int {
+	iso, ok := iso4217[c]
+	if !ok {
+		return notApplicable
+	}
+	return iso.minorUnits
+}
+
+// Name returns the English ISO 4217 name of the currency, e.g. "Swiss Franc" for CHF, or "" if c is not valid
+//
+// See CurrencyInfo for the Polish names used by NBP.
+func (c Currency) Name() string {
+	return iso4217[c].name
+}
+
+// CurrencyInfo describes a currency listed in NBP tables
+type CurrencyInfo struct {
+	Code Currency
+	// Name is the Polish name of the currency, as published by NBP
+	Name string
+	// Table is the table publishing the mid rate of the currency, TableA or TableB
+	Table Table
+}
+
+// Currencies returns the currencies listed in NBP tables A and B, ordered by code
+//
+// The catalog is built from the most recently published tables A and B, which are cached for a day.
+func (n *NBP) Currencies() ([]CurrencyInfo, error) {
+	return n.CurrenciesContext(context.Background())
+}
+
+// CurrenciesContext is like Currencies, but the NBP API calls are bound to ctx
+func (n *NBP) CurrenciesContext(ctx context.Context) ([]CurrencyInfo, error) {
+	seen := make(map[Currency]bool)
+	var currencies []CurrencyInfo
+	for _, table := range []Table{TableA, TableB} {
+		apiTable, err := n.api.GetCurrentTable(ctx, nbpapi.Table(table))
+		if err != nil {
+			return nil, err
+		}
+		for _, rate := range apiTable.Rates {
+			code := Currency(rate.Code)
+			if seen[code] {
+				continue
+			}
+			seen[code] = true
+			currencies = append(currencies, CurrencyInfo{Code: code, Name: rate.Currency, Table: table})
+		}
+	}
+	sort.Slice(currencies, func(i, j int) bool {
+		return currencies[i].Code < currencies[j].Code
+	})
+	return currencies, nil
+}
+
+// LookupCurrency returns the catalog entry of a given currency, or ErrUnknownCurrency if it's not in the catalog
+//
+// See Currencies for how the catalog is built.
+func (n *NBP) LookupCurrency(curr Currency) (*CurrencyInfo, error) {
+	return n.LookupCurrencyContext(context.Background(), curr)
+}
+
+// LookupCurrencyContext is like LookupCurrency, but the NBP API calls are bound to ctx
+func (n *NBP) LookupCurrencyContext(ctx context.Context, curr Currency) (*CurrencyInfo, error) {
+	if err := curr.Validate(); err != nil {
+		return nil, err
+	}
+	currencies, err := n.CurrenciesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range currencies {
+		if info.Code == curr {
+			return &info, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownCurrency, curr)
+}
@@ -0,0 +1,169 @@
+package gonbp
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/igor-kupczynski/gonbp/internal/nbpapi"
+	"github.com/shopspring/decimal"
+)
+
+func TestParseCurrency(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Currency
+		wantErr error
+	}{
+		{name: "Upper case", s: "EUR", want: EUR},
+		{name: "Lower case with whitespace", s: " chf\n", want: CHF},
+		{name: "Too short", s: "EU", wantErr: ErrInvalidCurrency},
+		{name: "Too long", s: "DOGE", wantErr: ErrInvalidCurrency},
+		{name: "Not letters", s: "E1R", wantErr: ErrInvalidCurrency},
+		{name: "Not ASCII", s: "EŁR", wantErr: ErrInvalidCurrency},
+		{name: "Not ISO 4217", s: "XYZ", wantErr: ErrInvalidCurrency},
+		{name: "Withdrawn but published by NBP", s: "hrk", want: "HRK"},
+		{name: "Replaced by the euro", s: "SIT", want: "SIT"},
+		{name: "Redenominated", s: "TRL", want: "TRL"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCurrency(tt.s)
+			if diff := cmp.Diff(tt.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ParseCurrency() error mismatch (-want +got):\n%s", diff)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCurrency() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNBP_Currencies(t *testing.T) {
+	n := testNBP(&mockClient{urls: map[string]mockResponse{
+		"A/current": {table: &nbpapi.ExchangeTable{
+			Table: "A",
+			Rates: []nbpapi.TableRate{
+				{Currency: "euro", Code: "EUR", Mid: decimal.NewFromFloat(4.6378)},
+				{Currency: "dolar amerykański", Code: "USD", Mid: decimal.NewFromFloat(4.2865)},
+			},
+		}},
+		"B/current": {table: &nbpapi.ExchangeTable{
+			Table: "B",
+			Rates: []nbpapi.TableRate{
+				{Currency: "afgani (Afganistan)", Code: "AFN", Mid: decimal.NewFromFloat(0.048802)},
+				{Currency: "euro", Code: "EUR", Mid: decimal.NewFromFloat(4.6378)},
+			},
+		}},
+	}})
+
+	t.Run("Catalog from tables A and B", func(t *testing.T) {
+		want := []CurrencyInfo{
+			{Code: "AFN", Name: "afgani (Afganistan)", Table: TableB},
+			{Code: EUR, Name: "euro", Table: TableA},
+			{Code: USD, Name: "dolar amerykański", Table: TableA},
+		}
+		got, err := n.Currencies()
+		if err != nil {
+			t.Errorf("Currencies() error = %v, want no error", err)
+			return
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Currencies() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Lookup a listed currency", func(t *testing.T) {
+		want := &CurrencyInfo{Code: "AFN", Name: "afgani (Afganistan)", Table: TableB}
+		got, err := n.LookupCurrency("AFN")
+		if err != nil {
+			t.Errorf("LookupCurrency() error = %v, want no error", err)
+			return
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("LookupCurrency() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Lookup an unknown currency", func(t *testing.T) {
+		_, err := n.LookupCurrency(CHF)
+		if diff := cmp.Diff(ErrUnknownCurrency, err, cmpopts.EquateErrors()); diff != "" {
+			t.Errorf("LookupCurrency() error mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestCurrency_ISO(t *testing.T) {
+	tests := []struct {
+		curr       Currency
+		numeric    string
+		minorUnits int
+		name       string
+		wantErr    error
+	}{
+		{curr: EUR, numeric: "978", minorUnits: 2, name: "Euro"},
+		{curr: "JPY", numeric: "392", minorUnits: 0, name: "Yen"},
+		{curr: "KWD", numeric: "414", minorUnits: 3, name: "Kuwaiti Dinar"},
+		{curr: "XDR", numeric: "960", minorUnits: -1, name: "SDR (Special Drawing Right)"},
+		{curr: "DOGE", numeric: "", minorUnits: -1, name: "", wantErr: ErrInvalidCurrency},
+		{curr: "eur", numeric: "", minorUnits: -1, name: "", wantErr: ErrInvalidCurrency},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(string(tt.curr), func(t *testing.T) {
+			if diff := cmp.Diff(tt.wantErr, tt.curr.Validate(), cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Validate() error mismatch (-want +got):\n%s", diff)
+			}
+			if got := tt.curr.Numeric(); got != tt.numeric {
+				t.Errorf("Numeric() = %q, want %q", got, tt.numeric)
+			}
+			if got := tt.curr.MinorUnits(); got != tt.minorUnits {
+				t.Errorf("MinorUnits() = %d, want %d", got, tt.minorUnits)
+			}
+			if got := tt.curr.Name(); got != tt.name {
+				t.Errorf("Name() = %q, want %q", got, tt.name)
+			}
+		})
+	}
+}
+
+func TestNBP_InvalidCurrency(t *testing.T) {
+	// The mock panics on any API call
+	n := testNBP(&mockClient{})
+	calls := map[string]func() error{
+		"Rate": func() error {
+			_, err := n.Rate("DOGE", day(2022, 4, 15))
+			return err
+		},
+		"BidAskRate": func() error {
+			_, err := n.BidAskRate("DOGE", day(2022, 4, 15))
+			return err
+		},
+		"RateRange": func() error {
+			_, err := n.RateRange("DOGE", day(2022, 4, 14), day(2022, 4, 19))
+			return err
+		},
+		"PreviousRate": func() error {
+			_, err := n.PreviousRate("DOGE", day(2022, 4, 18))
+			return err
+		},
+		"LastRates": func() error {
+			_, err := n.LastRates("DOGE", 2)
+			return err
+		},
+		"Current": func() error {
+			_, err := n.Current("DOGE")
+			return err
+		},
+	}
+	for name, call := range calls {
+		call := call
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(ErrInvalidCurrency, call(), cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("error mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package gonbp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/igor-kupczynski/gonbp/internal/publication"
+)
+
+const dateLayout = "2006-01-02"
+
+// Date is a civil date, a day in the calendar without the time of day and the time zone
+//
+// NBP publishes the tables for civil dates, so two Date values for the same day are always equal, unlike time.Time
+// values in different zones. The zero value is not a valid date.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// NewDate returns the date for a given year, month and day, normalized like time.Date, e.g. April 31 is May 1
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateOf returns the civil date of a given time in its own location, as returned by t.Date()
+//
+// Use t.In(Warsaw) to take the date in Warsaw at that instant instead.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// Today returns the current civil date in Warsaw, the day of the tables NBP publishes today
+func Today() Date {
+	return warsawToday(time.Now())
+}
+
+// warsawToday returns the civil date in Warsaw at a given time
+func warsawToday(now time.Time) Date {
+	return DateOf(publication.Today(now))
+}
+
+// ParseDate parses a date in the YYYY-MM-DD format
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("can't parse date %q: %w", s, err)
+	}
+	return DateOf(t), nil
+}
+
+// String returns the date in the YYYY-MM-DD format
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// IsZero reports whether d is the zero value
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// IsValid reports whether d is a valid date, e.g. April 31 is not
+func (d Date) IsValid() bool {
+	return NewDate(d.Year, d.Month, d.Day) == d
+}
+
+// Time returns the start of the day as midnight UTC
+func (d Date) Time() time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, time.UTC)
+}
+
+// In returns the start of the day in a given location
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// Weekday returns the day of the week of d
+func (d Date) Weekday() time.Weekday {
+	return d.Time().Weekday()
+}
+
+// AddDays returns the date n days after d, or before d if n is negative
+func (d Date) AddDays(n int) Date {
+	return NewDate(d.Year, d.Month, d.Day+n)
+}
+
+// AddDate returns the date the given number of years, months and days after d, normalized like time.Time.AddDate
+func (d Date) AddDate(years, months, days int) Date {
+	return NewDate(d.Year+years, d.Month+time.Month(months), d.Day+days)
+}
+
+// DaysSince returns the number of days from s to d, negative if d is before s
+func (d Date) DaysSince(s Date) int {
+	return int(d.Time().Sub(s.Time()).Hours()) / 24
+}
+
+// Before reports whether d is before e
+func (d Date) Before(e Date) bool {
+	return d.Compare(e) < 0
+}
+
+// After reports whether d is after e
+func (d Date) After(e Date) bool {
+	return d.Compare(e) > 0
+}
+
+// Compare returns -1 if d is before e, 0 if they are the same day and 1 if d is after e
+func (d Date) Compare(e Date) int {
+	switch {
+	case d.Year != e.Year:
+		return sign(d.Year - e.Year)
+	case d.Month != e.Month:
+		return sign(int(d.Month - e.Month))
+	default:
+		return sign(d.Day - e.Day)
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler, the date is encoded in the YYYY-MM-DD format
+//
+// JSON encodes Date as a string with MarshalText.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, the date must be in the YYYY-MM-DD format
+func (d *Date) UnmarshalText(data []byte) error {
+	parsed, err := ParseDate(string(data))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
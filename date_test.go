@@ -0,0 +1,110 @@
+package gonbp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDateOf(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		t    time.Time
+		want Date
+	}{
+		{name: "Midnight UTC", t: day(2022, 4, 15), want: date(2022, 4, 15)},
+		{name: "Late evening UTC", t: time.Date(2022, 4, 15, 23, 30, 0, 0, time.UTC), want: date(2022, 4, 15)},
+		{name: "Morning in New York", t: time.Date(2022, 4, 15, 9, 0, 0, 0, newYork), want: date(2022, 4, 15)},
+		{name: "Evening in New York", t: time.Date(2022, 4, 15, 20, 0, 0, 0, newYork), want: date(2022, 4, 15)},
+		{name: "Evening in New York in Warsaw", t: time.Date(2022, 4, 15, 20, 0, 0, 0, newYork).In(Warsaw), want: date(2022, 4, 16)},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DateOf(tt.t); got != tt.want {
+				t.Errorf("DateOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Date
+		wantErr bool
+	}{
+		{name: "Valid date", s: "2022-04-15", want: Date{Year: 2022, Month: time.April, Day: 15}},
+		{name: "Invalid day", s: "2022-04-31", wantErr: true},
+		{name: "Time of day", s: "2022-04-15T12:00:00Z", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDate(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDate_Arithmetic(t *testing.T) {
+	d := date(2022, 4, 15)
+	if got, want := d.AddDays(17), date(2022, 5, 2); got != want {
+		t.Errorf("AddDays() = %v, want %v", got, want)
+	}
+	if got, want := d.AddDays(-15), date(2022, 3, 31); got != want {
+		t.Errorf("AddDays() = %v, want %v", got, want)
+	}
+	if got, want := date(2024, 2, 29).AddDate(1, 0, 0), date(2025, 3, 1); got != want {
+		t.Errorf("AddDate() = %v, want %v", got, want)
+	}
+	if got, want := date(2022, 4, 19).DaysSince(d), 4; got != want {
+		t.Errorf("DaysSince() = %v, want %v", got, want)
+	}
+	if !d.Before(date(2022, 4, 16)) || d.After(date(2022, 5, 1)) || d.Compare(date(2022, 4, 15)) != 0 {
+		t.Errorf("Before(), After() or Compare() mismatch for %v", d)
+	}
+	if got, want := NewDate(2022, 4, 31), date(2022, 5, 1); got != want {
+		t.Errorf("NewDate() = %v, want %v", got, want)
+	}
+	if (Date{Year: 2022, Month: time.April, Day: 31}).IsValid() || (Date{}).IsValid() || !d.IsValid() {
+		t.Errorf("IsValid() mismatch")
+	}
+}
+
+func TestDate_JSON(t *testing.T) {
+	type payload struct {
+		Day Date `json:"day"`
+	}
+	data, err := json.Marshal(payload{Day: date(2022, 4, 15)})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v, want no error", err)
+	}
+	if got, want := string(data), `{"day":"2022-04-15"}`; got != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+
+	var got payload
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v, want no error", err)
+	}
+	if diff := cmp.Diff(payload{Day: date(2022, 4, 15)}, got); diff != "" {
+		t.Errorf("Unmarshal() mismatch (-want +got):\n%s", diff)
+	}
+	if err := json.Unmarshal([]byte(`{"day":"15.04.2022"}`), &got); err == nil {
+		t.Errorf("Unmarshal() error = nil, want an error for an invalid date")
+	}
+}
@@ -6,4 +6,9 @@ require (
 	github.com/google/go-cmp v0.5.7
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/shopspring/decimal v1.3.1
+	go.etcd.io/bbolt v1.3.6
+	golang.org/x/sys v0.7.0
+	golang.org/x/time v0.3.0
 )
+
+require golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543 // indirect
@@ -0,0 +1,112 @@
+package gonbp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// GoldPrice represents the NBP price of 1 g of gold for a given date, in PLN
+type GoldPrice struct {
+	Day   Date
+	Price decimal.Decimal
+}
+
+// Gold is like GoldOn for the civil date of day
+func (n *NBP) Gold(day time.Time) (*GoldPrice, error) {
+	return n.GoldOnContext(context.Background(), DateOf(day))
+}
+
+// GoldContext is like Gold, but the NBP API call is bound to ctx
+func (n *NBP) GoldContext(ctx context.Context, day time.Time) (*GoldPrice, error) {
+	return n.GoldOnContext(ctx, DateOf(day))
+}
+
+// GoldOn returns the price of gold for a given date
+func (n *NBP) GoldOn(day Date) (*GoldPrice, error) {
+	return n.GoldOnContext(context.Background(), day)
+}
+
+// GoldOnContext is like GoldOn, but the NBP API call is bound to ctx
+func (n *NBP) GoldOnContext(ctx context.Context, day Date) (*GoldPrice, error) {
+	price, err := n.api.GetGold(ctx, day.Time())
+	if err != nil {
+		return nil, err
+	}
+	priceDay, err := parseDay(price.Date)
+	if err != nil {
+		return nil, err
+	}
+	return &GoldPrice{Day: priceDay, Price: price.Price}, nil
+}
+
+// PreviousGold is like PreviousGoldOn for the civil date of day
+func (n *NBP) PreviousGold(day time.Time) (*GoldPrice, error) {
+	return n.PreviousGoldOnContext(context.Background(), DateOf(day))
+}
+
+// PreviousGoldContext is like PreviousGold, but the NBP API calls are bound to ctx
+func (n *NBP) PreviousGoldContext(ctx context.Context, day time.Time) (*GoldPrice, error) {
+	return n.PreviousGoldOnContext(ctx, DateOf(day))
+}
+
+// PreviousGoldOn returns the price of gold for the last working day before the given day
+//
+// PreviousGoldOn checks at most the number of days set with WithMaxLookback, and returns ErrLookbackExceeded if none
+// of them has a published price.
+func (n *NBP) PreviousGoldOn(day Date) (*GoldPrice, error) {
+	return n.PreviousGoldOnContext(context.Background(), day)
+}
+
+// PreviousGoldOnContext is like PreviousGoldOn, but the NBP API calls are bound to ctx
+func (n *NBP) PreviousGoldOnContext(ctx context.Context, day Date) (*GoldPrice, error) {
+	var price *GoldPrice
+	err := n.walk(ctx, day, -1, func(day Date) (err error) {
+		price, err = n.GoldOnContext(ctx, day)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// GoldRange is like GoldBetween for the civil dates of from and to
+func (n *NBP) GoldRange(from, to time.Time) ([]GoldPrice, error) {
+	return n.GoldBetweenContext(context.Background(), DateOf(from), DateOf(to))
+}
+
+// GoldRangeContext is like GoldRange, but the NBP API calls are bound to ctx
+func (n *NBP) GoldRangeContext(ctx context.Context, from, to time.Time) ([]GoldPrice, error) {
+	return n.GoldBetweenContext(ctx, DateOf(from), DateOf(to))
+}
+
+// GoldBetween returns the prices of gold for every day between from and to (inclusive) with published prices
+//
+// The prices are ordered by day. Days without publication, e.g. weekends, are skipped. Periods longer than 367 days,
+// the NBP API limit, are fetched in multiple requests.
+func (n *NBP) GoldBetween(from, to Date) ([]GoldPrice, error) {
+	return n.GoldBetweenContext(context.Background(), from, to)
+}
+
+// GoldBetweenContext is like GoldBetween, but the NBP API calls are bound to ctx
+func (n *NBP) GoldBetweenContext(ctx context.Context, from, to Date) ([]GoldPrice, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("invalid range: %s is before %s", to, from)
+	}
+	apiPrices, err := n.api.GetGoldRange(ctx, from.Time(), to.Time())
+	if err != nil {
+		return nil, err
+	}
+	prices := make([]GoldPrice, 0, len(apiPrices))
+	for _, price := range apiPrices {
+		priceDay, err := parseDay(price.Date)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, GoldPrice{Day: priceDay, Price: price.Price})
+	}
+	return prices, nil
+}
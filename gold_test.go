@@ -0,0 +1,115 @@
+package gonbp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/igor-kupczynski/gonbp/internal/nbpapi"
+	"github.com/shopspring/decimal"
+)
+
+func TestNBP_Gold(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    map[string]mockResponse
+		day     time.Time
+		want    *GoldPrice
+		wantErr error
+	}{
+		{
+			name: "Positive case",
+			urls: map[string]mockResponse{
+				"GOLD/2022-04-15": {gold: []nbpapi.GoldPrice{{Date: "2022-04-15", Price: decimal.NewFromFloat(268.44)}}},
+			},
+			day:  day(2022, 4, 15),
+			want: &GoldPrice{Day: date(2022, 4, 15), Price: decimal.NewFromFloat(268.44)},
+		},
+		{
+			name: "No price for a given day",
+			urls: map[string]mockResponse{
+				"GOLD/2022-04-16": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+			},
+			day:     day(2022, 4, 16),
+			wantErr: ErrNoExchangeRateForGivenDay,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			n := testNBP(&mockClient{urls: tt.urls})
+			got, err := n.Gold(tt.day)
+			if diff := cmp.Diff(tt.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Gold() error mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Gold() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNBP_PreviousGold(t *testing.T) {
+	n := testNBP(&mockClient{urls: map[string]mockResponse{
+		"GOLD/2022-04-17": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+		"GOLD/2022-04-16": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+		"GOLD/2022-04-15": {gold: []nbpapi.GoldPrice{{Date: "2022-04-15", Price: decimal.NewFromFloat(268.44)}}},
+	}})
+	want := &GoldPrice{Day: date(2022, 4, 15), Price: decimal.NewFromFloat(268.44)}
+	got, err := n.PreviousGold(day(2022, 4, 18))
+	if err != nil {
+		t.Errorf("PreviousGold() error = %v, want no error", err)
+		return
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("PreviousGold() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNBP_GoldRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    map[string]mockResponse
+		from    time.Time
+		to      time.Time
+		want    []GoldPrice
+		wantErr bool
+	}{
+		{
+			name: "Positive case",
+			urls: map[string]mockResponse{
+				"GOLD/2022-04-14/2022-04-19": {gold: []nbpapi.GoldPrice{
+					{Date: "2022-04-14", Price: decimal.NewFromFloat(267.31)},
+					{Date: "2022-04-19", Price: decimal.NewFromFloat(269.02)},
+				}},
+			},
+			from: day(2022, 4, 14),
+			to:   day(2022, 4, 19),
+			want: []GoldPrice{
+				{Day: date(2022, 4, 14), Price: decimal.NewFromFloat(267.31)},
+				{Day: date(2022, 4, 19), Price: decimal.NewFromFloat(269.02)},
+			},
+		},
+		{
+			name:    "Inverted range",
+			from:    day(2022, 4, 19),
+			to:      day(2022, 4, 14),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			n := testNBP(&mockClient{urls: tt.urls})
+			got, err := n.GoldRange(tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GoldRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GoldRange() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -2,8 +2,11 @@
 package gonbp
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/igor-kupczynski/gonbp/cache"
+	"github.com/igor-kupczynski/gonbp/calendar"
 	"github.com/igor-kupczynski/gonbp/internal/cachedapi"
 	"github.com/igor-kupczynski/gonbp/internal/nbpapi"
 	"net/http"
@@ -14,57 +17,309 @@ import (
 )
 
 type nbpAPIClient interface {
-	Get(curr string, day time.Time) (*nbpapi.Rates, error)
+	Get(ctx context.Context, table nbpapi.Table, curr string, day time.Time) (*nbpapi.Rates, error)
+	GetRange(ctx context.Context, table nbpapi.Table, curr string, from, to time.Time) (*nbpapi.Rates, error)
+	GetLast(ctx context.Context, table nbpapi.Table, curr string, n int) (*nbpapi.Rates, error)
+	GetToday(ctx context.Context, table nbpapi.Table, curr string) (*nbpapi.Rates, error)
+	GetCurrent(ctx context.Context, table nbpapi.Table, curr string) (*nbpapi.Rates, error)
+	GetTable(ctx context.Context, table nbpapi.Table, day time.Time) (*nbpapi.ExchangeTable, error)
+	GetCurrentTable(ctx context.Context, table nbpapi.Table) (*nbpapi.ExchangeTable, error)
+	GetGold(ctx context.Context, day time.Time) (*nbpapi.GoldPrice, error)
+	GetGoldRange(ctx context.Context, from, to time.Time) ([]nbpapi.GoldPrice, error)
 }
 
 // NBP is the NBP API client
 type NBP struct {
-	api nbpAPIClient
+	api         nbpAPIClient
+	table       Table
+	rounding    RoundingMode
+	maxLookback int
+	now         func() time.Time
 }
 
 // Init returns *NBP instance with a given httpClient
-func Init(cacheDir string, client *http.Client) *NBP {
-	return &NBP{api: cachedapi.Init(cacheDir, client)}
+//
+// The responses are cached in files under cacheDir, unless a different cache is set with WithCache. The instance
+// fetches mid rates from NBP table A, see WithTable to use a different table.
+func Init(cacheDir string, client *http.Client, opts ...Option) *NBP {
+	o := options{
+		maxLookback: DefaultMaxLookback,
+		retry:       DefaultRetryPolicy,
+		rateLimit:   DefaultRateLimit,
+		rateBurst:   DefaultRateLimitBurst,
+		maxInFlight: DefaultMaxInFlight,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.cache == nil {
+		o.cache = cache.NewFile(cacheDir)
+	}
+	api := nbpapi.Init(
+		client,
+		nbpapi.WithRetryPolicy(nbpapi.RetryPolicy(o.retry)),
+		nbpapi.WithRateLimit(o.rateLimit, o.rateBurst),
+		nbpapi.WithMaxInFlight(o.maxInFlight),
+	)
+	return &NBP{
+		api:         cachedapi.Init(o.cache, api),
+		table:       TableA,
+		maxLookback: o.maxLookback,
+		now:         time.Now,
+	}
+}
+
+// WithTable returns a copy of *NBP instance which fetches mid rates from a given table
+//
+// The copy shares the cache and the http client with the original instance.
+func (n *NBP) WithTable(table Table) *NBP {
+	c := *n
+	c.table = table
+	return &c
+}
+
+// MaxLookback returns the number of days PreviousRate and NextRate check before giving up, see WithMaxLookback
+func (n *NBP) MaxLookback() int {
+	return n.maxLookback
 }
 
 // Default returns *NBP instance using http.DefaultClient and $HOME/.config/nbp
-func Default() (*NBP, error) {
+func Default(opts ...Option) (*NBP, error) {
 	cacheDir, err := homedir.Expand("~/.config/nbp")
 	if err != nil {
 		return nil, err
 	}
-	return Init(cacheDir, http.DefaultClient), nil
+	return Init(cacheDir, http.DefaultClient, opts...), nil
 }
 
-// Currency enumerates supported currencies
-type Currency string
+// ErrNoExchangeRateForGivenDay represents a failure where there are no published rates for a given day
+var ErrNoExchangeRateForGivenDay = nbpapi.ErrNoExchangeRateForGivenDay
+
+// ErrNoRatesForCurrency represents a failure where NBP doesn't publish exchange rates for the given currency
+var ErrNoRatesForCurrency = nbpapi.ErrNoRatesForCurrency
+
+// ErrLookbackExceeded represents a failure where no rate was published within the maximum lookback from a given day
+//
+// ErrLookbackExceeded wraps ErrNoExchangeRateForGivenDay.
+type ErrLookbackExceeded struct {
+	// Day is the day the search started from
+	Day Date
+	// Checked is the number of days checked
+	Checked int
+	// Last is the last day checked
+	Last Date
+}
+
+func (e ErrLookbackExceeded) Error() string {
+	return fmt.Sprintf("no exchange rate within %d days from %s, checked up to %s", e.Checked, e.Day, e.Last)
+}
+
+func (e ErrLookbackExceeded) Unwrap() error {
+	return ErrNoExchangeRateForGivenDay
+}
+
+// Table enumerates NBP exchange rate tables
+type Table string
 
 const (
-	CHF Currency = "CHF"
-	EUR Currency = "EUR"
-	USD Currency = "USD"
+	// TableA contains the mid rates of the most traded currencies, published every working day
+	TableA Table = "A"
+	// TableB contains the mid rates of the remaining currencies, published weekly on Wednesdays
+	TableB Table = "B"
+	// TableC contains the buy (bid) and sell (ask) rates, published every working day
+	TableC Table = "C"
 )
 
 // Rate represents the currency exchange rate for a given date
 type Rate struct {
 	TableNo string
-	Day     time.Time
+	Day     Date
 	Mid     decimal.Decimal
 }
 
-// Rate returns the currency exchange rate for a given date from NBP table A
+// BidAskRate represents the currency buy and sell rates for a given date from NBP table C
+type BidAskRate struct {
+	TableNo    string
+	TradingDay Date
+	Day        Date
+	Bid        decimal.Decimal
+	Ask        decimal.Decimal
+}
+
+// ExchangeTable represents a whole NBP exchange rate table published for a given date
+type ExchangeTable struct {
+	Table      Table
+	TableNo    string
+	TradingDay Date
+	Day        Date
+	Rates      []TableRate
+}
+
+// TableRate represents a single currency rate in an exchange rate table
+//
+// Tables A and B publish the Mid rate, table C publishes the Bid and Ask rates.
+type TableRate struct {
+	Currency Currency
+	Name     string
+	Mid      decimal.Decimal
+	Bid      decimal.Decimal
+	Ask      decimal.Decimal
+}
+
+// Find returns the rate of a given currency in the table, or false if the table doesn't list the currency
+func (t *ExchangeTable) Find(curr Currency) (TableRate, bool) {
+	for _, rate := range t.Rates {
+		if rate.Currency == curr {
+			return rate, true
+		}
+	}
+	return TableRate{}, false
+}
+
+// Rate is like RateOn for the civil date of day
 func (n *NBP) Rate(curr Currency, day time.Time) (*Rate, error) {
-	apiRates, err := n.api.Get(string(curr), day)
+	return n.RateOnContext(context.Background(), curr, DateOf(day))
+}
+
+// RateContext is like Rate, but the NBP API call is bound to ctx
+func (n *NBP) RateContext(ctx context.Context, curr Currency, day time.Time) (*Rate, error) {
+	return n.RateOnContext(ctx, curr, DateOf(day))
+}
+
+// RateOn returns the currency exchange rate for a given date from NBP table A, or the table selected with WithTable
+//
+// For today RateOn returns ErrNotYetPublished until the table is published, see PublicationWindow.
+func (n *NBP) RateOn(curr Currency, day Date) (*Rate, error) {
+	return n.RateOnContext(context.Background(), curr, day)
+}
+
+// RateOnContext is like RateOn, but the NBP API call is bound to ctx
+func (n *NBP) RateOnContext(ctx context.Context, curr Currency, day Date) (*Rate, error) {
+	if err := n.checkMidRates(); err != nil {
+		return nil, err
+	}
+	rate, err := n.dailyRate(ctx, n.table, curr, day)
 	if err != nil {
 		return nil, err
 	}
-	if len(apiRates.Rates) != 1 {
-		return nil, fmt.Errorf("expectation failed: wanted a single rate, instead got %v", apiRates.Rates)
+	return midRate(*rate)
+}
+
+// RateRange is like RatesBetween for the civil dates of from and to
+func (n *NBP) RateRange(curr Currency, from, to time.Time) ([]Rate, error) {
+	return n.RatesBetweenContext(context.Background(), curr, DateOf(from), DateOf(to))
+}
+
+// RateRangeContext is like RateRange, but the NBP API calls are bound to ctx
+func (n *NBP) RateRangeContext(ctx context.Context, curr Currency, from, to time.Time) ([]Rate, error) {
+	return n.RatesBetweenContext(ctx, curr, DateOf(from), DateOf(to))
+}
+
+// RatesBetween returns the currency exchange rates for every day between from and to (inclusive) with published rates
+//
+// The rates are ordered by day. Days without publication, e.g. weekends, are skipped. Periods longer than 93 days,
+// the NBP API limit, are fetched in multiple requests.
+func (n *NBP) RatesBetween(curr Currency, from, to Date) ([]Rate, error) {
+	return n.RatesBetweenContext(context.Background(), curr, from, to)
+}
+
+// RatesBetweenContext is like RatesBetween, but the NBP API calls are bound to ctx
+func (n *NBP) RatesBetweenContext(ctx context.Context, curr Currency, from, to Date) ([]Rate, error) {
+	if err := n.checkMidRates(); err != nil {
+		return nil, err
 	}
-	rate := apiRates.Rates[0]
-	effectiveDay, err := time.Parse("2006-01-02", rate.EffectiveDate)
+	if err := curr.Validate(); err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("invalid range: %s is before %s", to, from)
+	}
+	apiRates, err := n.api.GetRange(ctx, nbpapi.Table(n.table), string(curr), from.Time(), to.Time())
 	if err != nil {
-		return nil, fmt.Errorf("expectation failed: can't parse date as day %s", rate.EffectiveDate)
+		return nil, err
+	}
+	return midRates(apiRates)
+}
+
+// LastRates returns the last n currency exchange rates published in NBP table A, or the table selected with WithTable
+//
+// The rates are ordered by day. NBP API returns at most 255 last rates.
+func (n *NBP) LastRates(curr Currency, count int) ([]Rate, error) {
+	return n.LastRatesContext(context.Background(), curr, count)
+}
+
+// LastRatesContext is like LastRates, but the NBP API call is bound to ctx
+func (n *NBP) LastRatesContext(ctx context.Context, curr Currency, count int) ([]Rate, error) {
+	if err := n.checkMidRates(); err != nil {
+		return nil, err
+	}
+	if err := curr.Validate(); err != nil {
+		return nil, err
+	}
+	if count < 1 || count > nbpapi.MaxLast {
+		return nil, fmt.Errorf("invalid number of rates %d, must be between 1 and %d", count, nbpapi.MaxLast)
+	}
+	apiRates, err := n.api.GetLast(ctx, nbpapi.Table(n.table), string(curr), count)
+	if err != nil {
+		return nil, err
+	}
+	return midRates(apiRates)
+}
+
+// Today returns the currency exchange rate published today, as in Warsaw
+//
+// Today returns ErrNotYetPublished if the rate is expected today, but is not published yet, and
+// ErrNoExchangeRateForGivenDay if it's not published today at all.
+func (n *NBP) Today(curr Currency) (*Rate, error) {
+	return n.TodayContext(context.Background(), curr)
+}
+
+// TodayContext is like Today, but the NBP API call is bound to ctx
+func (n *NBP) TodayContext(ctx context.Context, curr Currency) (*Rate, error) {
+	if err := n.checkMidRates(); err != nil {
+		return nil, err
+	}
+	if err := curr.Validate(); err != nil {
+		return nil, err
+	}
+	apiRates, err := n.api.GetToday(ctx, nbpapi.Table(n.table), string(curr))
+	if err != nil {
+		return nil, n.notPublishedYet(n.table, warsawToday(n.now()), err)
+	}
+	return singleMidRate(apiRates)
+}
+
+// Current returns the most recently published currency exchange rate
+func (n *NBP) Current(curr Currency) (*Rate, error) {
+	return n.CurrentContext(context.Background(), curr)
+}
+
+// CurrentContext is like Current, but the NBP API call is bound to ctx
+func (n *NBP) CurrentContext(ctx context.Context, curr Currency) (*Rate, error) {
+	if err := n.checkMidRates(); err != nil {
+		return nil, err
+	}
+	if err := curr.Validate(); err != nil {
+		return nil, err
+	}
+	apiRates, err := n.api.GetCurrent(ctx, nbpapi.Table(n.table), string(curr))
+	if err != nil {
+		return nil, err
+	}
+	return singleMidRate(apiRates)
+}
+
+func (n *NBP) checkMidRates() error {
+	if n.table == TableC {
+		return fmt.Errorf("table %s doesn't publish mid rates, use BidAskRate instead", n.table)
+	}
+	return nil
+}
+
+func midRate(rate nbpapi.DailyRate) (*Rate, error) {
+	effectiveDay, err := parseDay(rate.EffectiveDate)
+	if err != nil {
+		return nil, err
 	}
 	return &Rate{
 		TableNo: rate.No,
@@ -73,18 +328,228 @@ func (n *NBP) Rate(curr Currency, day time.Time) (*Rate, error) {
 	}, nil
 }
 
-// PreviousRate returns the currency exchange rate for the last working day before the given day
+func midRates(apiRates *nbpapi.Rates) ([]Rate, error) {
+	rates := make([]Rate, 0, len(apiRates.Rates))
+	for _, apiRate := range apiRates.Rates {
+		rate, err := midRate(apiRate)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, *rate)
+	}
+	return rates, nil
+}
+
+func singleMidRate(apiRates *nbpapi.Rates) (*Rate, error) {
+	if len(apiRates.Rates) != 1 {
+		return nil, fmt.Errorf("expectation failed: wanted a single rate, instead got %v", apiRates.Rates)
+	}
+	return midRate(apiRates.Rates[0])
+}
+
+// BidAskRate is like BidAskRateOn for the civil date of day
+func (n *NBP) BidAskRate(curr Currency, day time.Time) (*BidAskRate, error) {
+	return n.BidAskRateOnContext(context.Background(), curr, DateOf(day))
+}
+
+// BidAskRateContext is like BidAskRate, but the NBP API call is bound to ctx
+func (n *NBP) BidAskRateContext(ctx context.Context, curr Currency, day time.Time) (*BidAskRate, error) {
+	return n.BidAskRateOnContext(ctx, curr, DateOf(day))
+}
+
+// BidAskRateOn returns the currency buy and sell rates for a given date from NBP table C
+func (n *NBP) BidAskRateOn(curr Currency, day Date) (*BidAskRate, error) {
+	return n.BidAskRateOnContext(context.Background(), curr, day)
+}
+
+// BidAskRateOnContext is like BidAskRateOn, but the NBP API call is bound to ctx
+func (n *NBP) BidAskRateOnContext(ctx context.Context, curr Currency, day Date) (*BidAskRate, error) {
+	rate, err := n.dailyRate(ctx, TableC, curr, day)
+	if err != nil {
+		return nil, err
+	}
+	tradingDay, err := parseDay(rate.TradingDate)
+	if err != nil {
+		return nil, err
+	}
+	effectiveDay, err := parseDay(rate.EffectiveDate)
+	if err != nil {
+		return nil, err
+	}
+	return &BidAskRate{
+		TableNo:    rate.No,
+		TradingDay: tradingDay,
+		Day:        effectiveDay,
+		Bid:        rate.Bid,
+		Ask:        rate.Ask,
+	}, nil
+}
+
+// Table is like TableOn for the civil date of day
+func (n *NBP) Table(day time.Time) (*ExchangeTable, error) {
+	return n.TableOnContext(context.Background(), DateOf(day))
+}
+
+// TableContext is like Table, but the NBP API call is bound to ctx
+func (n *NBP) TableContext(ctx context.Context, day time.Time) (*ExchangeTable, error) {
+	return n.TableOnContext(ctx, DateOf(day))
+}
+
+// TableOn returns the rates of all currencies for a given date from NBP table A, or the table selected with WithTable
+//
+// The whole table is fetched in a single NBP API call. The per-currency rates from the table are cached, so the
+// following Rate calls for that day don't call the NBP API.
+func (n *NBP) TableOn(day Date) (*ExchangeTable, error) {
+	return n.TableOnContext(context.Background(), day)
+}
+
+// TableOnContext is like TableOn, but the NBP API call is bound to ctx
+func (n *NBP) TableOnContext(ctx context.Context, day Date) (*ExchangeTable, error) {
+	apiTable, err := n.api.GetTable(ctx, nbpapi.Table(n.table), day.Time())
+	if err != nil {
+		return nil, n.notPublishedYet(n.table, day, err)
+	}
+	effectiveDay, err := parseDay(apiTable.EffectiveDate)
+	if err != nil {
+		return nil, err
+	}
+	var tradingDay Date
+	if apiTable.TradingDate != "" {
+		if tradingDay, err = parseDay(apiTable.TradingDate); err != nil {
+			return nil, err
+		}
+	}
+	rates := make([]TableRate, 0, len(apiTable.Rates))
+	for _, rate := range apiTable.Rates {
+		rates = append(rates, TableRate{
+			Currency: Currency(rate.Code),
+			Name:     rate.Currency,
+			Mid:      rate.Mid,
+			Bid:      rate.Bid,
+			Ask:      rate.Ask,
+		})
+	}
+	return &ExchangeTable{
+		Table:      n.table,
+		TableNo:    apiTable.No,
+		TradingDay: tradingDay,
+		Day:        effectiveDay,
+		Rates:      rates,
+	}, nil
+}
+
+func (n *NBP) dailyRate(ctx context.Context, table Table, curr Currency, day Date) (*nbpapi.DailyRate, error) {
+	if err := curr.Validate(); err != nil {
+		return nil, err
+	}
+	apiRates, err := n.api.Get(ctx, nbpapi.Table(table), string(curr), day.Time())
+	if err != nil {
+		return nil, n.notPublishedYet(table, day, err)
+	}
+	if len(apiRates.Rates) != 1 {
+		return nil, fmt.Errorf("expectation failed: wanted a single rate, instead got %v", apiRates.Rates)
+	}
+	return &apiRates.Rates[0], nil
+}
+
+func parseDay(s string) (Date, error) {
+	day, err := ParseDate(s)
+	if err != nil {
+		return Date{}, fmt.Errorf("expectation failed: can't parse date as day %s", s)
+	}
+	return day, nil
+}
+
+// PreviousRate is like PreviousRateOn for the civil date of day
 func (n *NBP) PreviousRate(curr Currency, day time.Time) (*Rate, error) {
-	checkForDay := day.AddDate(0, 0, -1)
-	for {
-		rate, err := n.Rate(curr, checkForDay)
-		if errors.Is(err, nbpapi.ErrNoExchangeRateForGivenDay) {
-			checkForDay = checkForDay.AddDate(0, 0, -1)
+	return n.PreviousRateOnContext(context.Background(), curr, DateOf(day))
+}
+
+// PreviousRateContext is like PreviousRate, but the NBP API calls are bound to ctx
+func (n *NBP) PreviousRateContext(ctx context.Context, curr Currency, day time.Time) (*Rate, error) {
+	return n.PreviousRateOnContext(ctx, curr, DateOf(day))
+}
+
+// PreviousRateOn returns the currency exchange rate for the last working day before the given day
+//
+// PreviousRateOn checks at most the number of days set with WithMaxLookback, and returns ErrLookbackExceeded if none
+// of them has a published rate.
+func (n *NBP) PreviousRateOn(curr Currency, day Date) (*Rate, error) {
+	return n.PreviousRateOnContext(context.Background(), curr, day)
+}
+
+// PreviousRateOnContext is like PreviousRateOn, but the NBP API calls are bound to ctx
+//
+// Cancelling ctx stops the search for the last working day.
+func (n *NBP) PreviousRateOnContext(ctx context.Context, curr Currency, day Date) (*Rate, error) {
+	var rate *Rate
+	err := n.walk(ctx, day, -1, func(day Date) (err error) {
+		rate, err = n.RateOnContext(ctx, curr, day)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+// NextRate is like NextRateOn for the civil date of day
+func (n *NBP) NextRate(curr Currency, day time.Time) (*Rate, error) {
+	return n.NextRateOnContext(context.Background(), curr, DateOf(day))
+}
+
+// NextRateContext is like NextRate, but the NBP API calls are bound to ctx
+func (n *NBP) NextRateContext(ctx context.Context, curr Currency, day time.Time) (*Rate, error) {
+	return n.NextRateOnContext(ctx, curr, DateOf(day))
+}
+
+// NextRateOn returns the currency exchange rate for the first working day after the given day
+//
+// NextRateOn checks at most the number of days set with WithMaxLookback and never goes past today. It returns
+// ErrLookbackExceeded if none of the checked days has a published rate.
+func (n *NBP) NextRateOn(curr Currency, day Date) (*Rate, error) {
+	return n.NextRateOnContext(context.Background(), curr, day)
+}
+
+// NextRateOnContext is like NextRateOn, but the NBP API calls are bound to ctx
+//
+// Cancelling ctx stops the search for the next working day.
+func (n *NBP) NextRateOnContext(ctx context.Context, curr Currency, day Date) (*Rate, error) {
+	var rate *Rate
+	err := n.walk(ctx, day, 1, func(day Date) (err error) {
+		rate, err = n.RateOnContext(ctx, curr, day)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+// walk calls try for the days before (step -1) or after (step 1) the given day until it finds a published value
+//
+// try returns ErrNoExchangeRateForGivenDay if there is no value published for a given day. Weekends and public
+// holidays are skipped without calling try, but they still count towards the lookback.
+func (n *NBP) walk(ctx context.Context, day Date, step int, try func(day Date) error) error {
+	today := warsawToday(n.now())
+	checkForDay := day
+	for checked := 0; checked < n.maxLookback; checked++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		next := checkForDay.AddDays(step)
+		if step > 0 && next.After(today) {
+			return ErrLookbackExceeded{Day: day, Checked: checked, Last: checkForDay}
+		}
+		checkForDay = next
+		if !calendar.IsBusinessDay(checkForDay.Time()) {
 			continue
 		}
-		if err != nil {
-			return nil, err
+		err := try(checkForDay)
+		if errors.Is(err, nbpapi.ErrNoExchangeRateForGivenDay) {
+			continue
 		}
-		return rate, nil
+		return err
 	}
+	return ErrLookbackExceeded{Day: day, Checked: n.maxLookback, Last: checkForDay}
 }
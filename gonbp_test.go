@@ -1,6 +1,8 @@
 package gonbp
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/igor-kupczynski/gonbp/internal/nbpapi"
 	"github.com/shopspring/decimal"
@@ -8,14 +10,21 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func day(year, month, day int) time.Time {
 	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
 }
 
+func date(year, month, day int) Date {
+	return NewDate(year, time.Month(month), day)
+}
+
 type mockResponse struct {
 	rates *nbpapi.Rates
+	table *nbpapi.ExchangeTable
+	gold  []nbpapi.GoldPrice
 	err   error
 }
 
@@ -23,17 +32,82 @@ type mockClient struct {
 	urls map[string]mockResponse
 }
 
-func (m *mockClient) Get(curr string, day time.Time) (*nbpapi.Rates, error) {
-	url := fmt.Sprintf("%s/%s", curr, day.Format("2006-01-02"))
+func testNBP(api nbpAPIClient) *NBP {
+	return &NBP{
+		api:         api,
+		table:       TableA,
+		maxLookback: DefaultMaxLookback,
+		now:         time.Now,
+	}
+}
+
+func (m *mockClient) Get(ctx context.Context, table nbpapi.Table, curr string, day time.Time) (*nbpapi.Rates, error) {
+	return m.response(fmt.Sprintf("%s/%s/%s", table, curr, day.Format("2006-01-02")))
+}
+
+func (m *mockClient) GetRange(ctx context.Context, table nbpapi.Table, curr string, from, to time.Time) (*nbpapi.Rates, error) {
+	return m.response(fmt.Sprintf("%s/%s/%s/%s", table, curr, from.Format("2006-01-02"), to.Format("2006-01-02")))
+}
+
+func (m *mockClient) GetLast(ctx context.Context, table nbpapi.Table, curr string, n int) (*nbpapi.Rates, error) {
+	return m.response(fmt.Sprintf("%s/%s/last/%d", table, curr, n))
+}
+
+func (m *mockClient) GetToday(ctx context.Context, table nbpapi.Table, curr string) (*nbpapi.Rates, error) {
+	return m.response(fmt.Sprintf("%s/%s/today", table, curr))
+}
+
+func (m *mockClient) GetCurrent(ctx context.Context, table nbpapi.Table, curr string) (*nbpapi.Rates, error) {
+	return m.response(fmt.Sprintf("%s/%s", table, curr))
+}
+
+func (m *mockClient) GetTable(ctx context.Context, table nbpapi.Table, day time.Time) (*nbpapi.ExchangeTable, error) {
+	resp, err := m.lookup(fmt.Sprintf("%s/%s", table, day.Format("2006-01-02")))
+	if err != nil {
+		return nil, err
+	}
+	return resp.table, nil
+}
+
+func (m *mockClient) GetCurrentTable(ctx context.Context, table nbpapi.Table) (*nbpapi.ExchangeTable, error) {
+	resp, err := m.lookup(fmt.Sprintf("%s/current", table))
+	if err != nil {
+		return nil, err
+	}
+	return resp.table, nil
+}
+
+func (m *mockClient) GetGold(ctx context.Context, day time.Time) (*nbpapi.GoldPrice, error) {
+	resp, err := m.lookup(fmt.Sprintf("GOLD/%s", day.Format("2006-01-02")))
+	if err != nil {
+		return nil, err
+	}
+	return &resp.gold[0], nil
+}
+
+func (m *mockClient) GetGoldRange(ctx context.Context, from, to time.Time) ([]nbpapi.GoldPrice, error) {
+	resp, err := m.lookup(fmt.Sprintf("GOLD/%s/%s", from.Format("2006-01-02"), to.Format("2006-01-02")))
+	if err != nil {
+		return nil, err
+	}
+	return resp.gold, nil
+}
+
+func (m *mockClient) response(url string) (*nbpapi.Rates, error) {
+	resp, err := m.lookup(url)
+	if err != nil {
+		return nil, err
+	}
+	return resp.rates, nil
+}
+
+func (m *mockClient) lookup(url string) (mockResponse, error) {
 	var resp mockResponse
 	var ok bool
 	if resp, ok = m.urls[url]; !ok {
 		panic("response not set up for " + url)
 	}
-	if resp.err != nil {
-		return nil, resp.err
-	}
-	return resp.rates, nil
+	return resp, resp.err
 }
 
 func TestNBP_Rate(t *testing.T) {
@@ -48,7 +122,7 @@ func TestNBP_Rate(t *testing.T) {
 		{
 			name: "Positive case EUR",
 			urls: map[string]mockResponse{
-				"EUR/2022-04-15": {
+				"A/EUR/2022-04-15": {
 					rates: &nbpapi.Rates{
 						Table:    "A",
 						Currency: "euro",
@@ -67,7 +141,7 @@ func TestNBP_Rate(t *testing.T) {
 			day:  day(2022, 4, 15),
 			want: &Rate{
 				TableNo: "074/A/NBP/2022",
-				Day:     day(2022, 4, 15),
+				Day:     date(2022, 4, 15),
 				Mid:     decimal.NewFromFloat(4.6378),
 			},
 			wantErr: false,
@@ -75,7 +149,7 @@ func TestNBP_Rate(t *testing.T) {
 		{
 			name: "Positive case CHF",
 			urls: map[string]mockResponse{
-				"CHF/2021-04-15": {
+				"A/CHF/2021-04-15": {
 					rates: &nbpapi.Rates{
 						Table:    "A",
 						Currency: "frank szwajcarski",
@@ -94,7 +168,7 @@ func TestNBP_Rate(t *testing.T) {
 			day:  day(2021, 4, 15),
 			want: &Rate{
 				TableNo: "072/A/NBP/2021",
-				Day:     day(2021, 4, 15),
+				Day:     date(2021, 4, 15),
 				Mid:     decimal.NewFromFloat(4.1198),
 			},
 			wantErr: false,
@@ -102,7 +176,7 @@ func TestNBP_Rate(t *testing.T) {
 		{
 			name: "Not found for a given day",
 			urls: map[string]mockResponse{
-				"EUR/2022-04-16": {
+				"A/EUR/2022-04-16": {
 					err: nbpapi.ErrNoExchangeRateForGivenDay,
 				},
 			},
@@ -113,11 +187,11 @@ func TestNBP_Rate(t *testing.T) {
 		{
 			name: "Non existing currency",
 			urls: map[string]mockResponse{
-				"DOGE/2022-04-15": {
+				"A/BOV/2022-04-15": {
 					err: nbpapi.ErrNoExchangeRateForGivenDay,
 				},
 			},
-			curr:    "DOGE",
+			curr:    "BOV",
 			day:     day(2022, 4, 15),
 			wantErr: true,
 		},
@@ -125,9 +199,7 @@ func TestNBP_Rate(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			n := &NBP{
-				api: &mockClient{urls: tt.urls},
-			}
+			n := testNBP(&mockClient{urls: tt.urls})
 			got, err := n.Rate(tt.curr, tt.day)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Rate() error = %v, wantErr %v", err, tt.wantErr)
@@ -152,7 +224,7 @@ func TestNBP_PreviousRate(t *testing.T) {
 		{
 			name: "Go back a day",
 			urls: map[string]mockResponse{
-				"EUR/2022-04-15": {
+				"A/EUR/2022-04-15": {
 					rates: &nbpapi.Rates{
 						Table:    "A",
 						Currency: "euro",
@@ -171,7 +243,7 @@ func TestNBP_PreviousRate(t *testing.T) {
 			day:  day(2022, 4, 16),
 			want: &Rate{
 				TableNo: "074/A/NBP/2022",
-				Day:     day(2022, 4, 15),
+				Day:     date(2022, 4, 15),
 				Mid:     decimal.NewFromFloat(4.6378),
 			},
 			wantErr: false,
@@ -179,13 +251,13 @@ func TestNBP_PreviousRate(t *testing.T) {
 		{
 			name: "Go back over a long weekend",
 			urls: map[string]mockResponse{
-				"EUR/2022-04-17": {
+				"A/EUR/2022-04-17": {
 					err: nbpapi.ErrNoExchangeRateForGivenDay,
 				},
-				"EUR/2022-04-16": {
+				"A/EUR/2022-04-16": {
 					err: nbpapi.ErrNoExchangeRateForGivenDay,
 				},
-				"EUR/2022-04-15": {
+				"A/EUR/2022-04-15": {
 					rates: &nbpapi.Rates{
 						Table:    "A",
 						Currency: "euro",
@@ -204,7 +276,7 @@ func TestNBP_PreviousRate(t *testing.T) {
 			day:  day(2022, 4, 18),
 			want: &Rate{
 				TableNo: "074/A/NBP/2022",
-				Day:     day(2022, 4, 15),
+				Day:     date(2022, 4, 15),
 				Mid:     decimal.NewFromFloat(4.6378),
 			},
 			wantErr: false,
@@ -212,11 +284,11 @@ func TestNBP_PreviousRate(t *testing.T) {
 		{
 			name: "Non-existing currency",
 			urls: map[string]mockResponse{
-				"DOGE/2022-04-15": {
+				"A/BOV/2022-04-15": {
 					err: nbpapi.ErrNoRatesForCurrency,
 				},
 			},
-			curr:    "DOGE",
+			curr:    "BOV",
 			day:     day(2022, 4, 16),
 			wantErr: true,
 		},
@@ -224,9 +296,7 @@ func TestNBP_PreviousRate(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			n := &NBP{
-				api: &mockClient{urls: tt.urls},
-			}
+			n := testNBP(&mockClient{urls: tt.urls})
 			got, err := n.PreviousRate(tt.curr, tt.day)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Rate() error = %v, wantErr %v", err, tt.wantErr)
@@ -238,3 +308,535 @@ func TestNBP_PreviousRate(t *testing.T) {
 		})
 	}
 }
+
+func TestNBP_WithTable(t *testing.T) {
+	n := &NBP{
+		api: &mockClient{urls: map[string]mockResponse{
+			"B/AFN/2022-04-13": {
+				rates: &nbpapi.Rates{
+					Table:    "B",
+					Currency: "afgani (Afganistan)",
+					Code:     "AFN",
+					Rates: []nbpapi.DailyRate{
+						{
+							No:            "015/B/NBP/2022",
+							EffectiveDate: "2022-04-13",
+							Mid:           decimal.NewFromFloat(0.048802),
+						},
+					},
+				},
+			},
+		}},
+		table: TableA,
+	}
+
+	t.Run("Table B", func(t *testing.T) {
+		want := &Rate{
+			TableNo: "015/B/NBP/2022",
+			Day:     date(2022, 4, 13),
+			Mid:     decimal.NewFromFloat(0.048802),
+		}
+		got, err := n.WithTable(TableB).Rate("AFN", day(2022, 4, 13))
+		if err != nil {
+			t.Errorf("Rate() error = %v, want no error", err)
+			return
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Rate() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Table C has no mid rates", func(t *testing.T) {
+		_, err := n.WithTable(TableC).Rate(USD, day(2022, 4, 15))
+		if err == nil {
+			t.Errorf("Rate() error = nil, want an error")
+		}
+	})
+
+	t.Run("Original instance is not modified", func(t *testing.T) {
+		if n.table != TableA {
+			t.Errorf("table = %s, want %s", n.table, TableA)
+		}
+	})
+}
+
+func TestNBP_BidAskRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    map[string]mockResponse
+		curr    Currency
+		day     time.Time
+		want    *BidAskRate
+		wantErr bool
+	}{
+		{
+			name: "Positive case USD",
+			urls: map[string]mockResponse{
+				"C/USD/2022-04-15": {
+					rates: &nbpapi.Rates{
+						Table:    "C",
+						Currency: "dolar amerykański",
+						Code:     "USD",
+						Rates: []nbpapi.DailyRate{
+							{
+								No:            "074/C/NBP/2022",
+								TradingDate:   "2022-04-14",
+								EffectiveDate: "2022-04-15",
+								Bid:           decimal.NewFromFloat(4.2455),
+								Ask:           decimal.NewFromFloat(4.3313),
+							},
+						},
+					},
+				},
+			},
+			curr: USD,
+			day:  day(2022, 4, 15),
+			want: &BidAskRate{
+				TableNo:    "074/C/NBP/2022",
+				TradingDay: date(2022, 4, 14),
+				Day:        date(2022, 4, 15),
+				Bid:        decimal.NewFromFloat(4.2455),
+				Ask:        decimal.NewFromFloat(4.3313),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Not found for a given day",
+			urls: map[string]mockResponse{
+				"C/USD/2022-04-16": {
+					err: nbpapi.ErrNoExchangeRateForGivenDay,
+				},
+			},
+			curr:    USD,
+			day:     day(2022, 4, 16),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			n := testNBP(&mockClient{urls: tt.urls})
+			got, err := n.BidAskRate(tt.curr, tt.day)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BidAskRate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("BidAskRate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNBP_RateRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    map[string]mockResponse
+		curr    Currency
+		from    time.Time
+		to      time.Time
+		want    []Rate
+		wantErr bool
+	}{
+		{
+			name: "Skip days without publication",
+			urls: map[string]mockResponse{
+				"A/EUR/2022-04-14/2022-04-19": {
+					rates: &nbpapi.Rates{
+						Table:    "A",
+						Currency: "euro",
+						Code:     "EUR",
+						Rates: []nbpapi.DailyRate{
+							{No: "073/A/NBP/2022", EffectiveDate: "2022-04-14", Mid: decimal.NewFromFloat(4.6215)},
+							{No: "074/A/NBP/2022", EffectiveDate: "2022-04-15", Mid: decimal.NewFromFloat(4.6378)},
+							{No: "075/A/NBP/2022", EffectiveDate: "2022-04-19", Mid: decimal.NewFromFloat(4.6448)},
+						},
+					},
+				},
+			},
+			curr: EUR,
+			from: day(2022, 4, 14),
+			to:   day(2022, 4, 19),
+			want: []Rate{
+				{TableNo: "073/A/NBP/2022", Day: date(2022, 4, 14), Mid: decimal.NewFromFloat(4.6215)},
+				{TableNo: "074/A/NBP/2022", Day: date(2022, 4, 15), Mid: decimal.NewFromFloat(4.6378)},
+				{TableNo: "075/A/NBP/2022", Day: date(2022, 4, 19), Mid: decimal.NewFromFloat(4.6448)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "No publication in the period",
+			urls: map[string]mockResponse{
+				"A/EUR/2022-04-16/2022-04-18": {
+					rates: &nbpapi.Rates{Table: "A", Code: "EUR", Rates: []nbpapi.DailyRate{}},
+				},
+			},
+			curr:    EUR,
+			from:    day(2022, 4, 16),
+			to:      day(2022, 4, 18),
+			want:    []Rate{},
+			wantErr: false,
+		},
+		{
+			name:    "Inverted range",
+			curr:    EUR,
+			from:    day(2022, 4, 19),
+			to:      day(2022, 4, 14),
+			wantErr: true,
+		},
+		{
+			name: "Non-existing currency",
+			urls: map[string]mockResponse{
+				"A/BOV/2022-04-14/2022-04-19": {
+					err: nbpapi.ErrNoRatesForCurrency,
+				},
+			},
+			curr:    "BOV",
+			from:    day(2022, 4, 14),
+			to:      day(2022, 4, 19),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			n := testNBP(&mockClient{urls: tt.urls})
+			got, err := n.RateRange(tt.curr, tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RateRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("RateRange() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNBP_PreviousRateContext(t *testing.T) {
+	t.Run("Cancelled context stops the walk back", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		n := testNBP(&cancellingClient{
+			mockClient: mockClient{urls: map[string]mockResponse{
+				"A/EUR/2022-04-15": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+			}},
+			cancel: cancel,
+		})
+		_, err := n.PreviousRateContext(ctx, EUR, day(2022, 4, 18))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("PreviousRateContext() error = %v, want %v", err, context.Canceled)
+		}
+	})
+}
+
+// cancellingClient cancels the context after the first call
+type cancellingClient struct {
+	mockClient
+	cancel context.CancelFunc
+}
+
+func (c *cancellingClient) Get(ctx context.Context, table nbpapi.Table, curr string, day time.Time) (*nbpapi.Rates, error) {
+	defer c.cancel()
+	return c.mockClient.Get(ctx, table, curr, day)
+}
+
+func TestNBP_PreviousRateLookback(t *testing.T) {
+	n := testNBP(&mockClient{urls: map[string]mockResponse{
+		"A/EUR/2022-04-17": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+		"A/EUR/2022-04-16": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+	}})
+	n.maxLookback = 2
+
+	_, err := n.PreviousRate(EUR, day(2022, 4, 18))
+	want := ErrLookbackExceeded{Day: date(2022, 4, 18), Checked: 2, Last: date(2022, 4, 16)}
+	if diff := cmp.Diff(want, err); diff != "" {
+		t.Errorf("PreviousRate() error mismatch (-want +got):\n%s", diff)
+	}
+	if !errors.Is(err, ErrNoExchangeRateForGivenDay) {
+		t.Errorf("PreviousRate() error = %v, want it to wrap %v", err, ErrNoExchangeRateForGivenDay)
+	}
+}
+
+func TestNBP_PreviousRateSkipsHolidays(t *testing.T) {
+	// Only Good Friday is set up, the mock panics if the walk asks NBP about the Easter weekend or Easter Monday
+	n := testNBP(&mockClient{urls: map[string]mockResponse{
+		"A/EUR/2022-04-15": {
+			rates: &nbpapi.Rates{
+				Table:    "A",
+				Currency: "euro",
+				Code:     "EUR",
+				Rates: []nbpapi.DailyRate{
+					{
+						No:            "074/A/NBP/2022",
+						EffectiveDate: "2022-04-15",
+						Mid:           decimal.NewFromFloat(4.6378),
+					},
+				},
+			},
+		},
+	}})
+
+	got, err := n.PreviousRate(EUR, day(2022, 4, 19))
+	if err != nil {
+		t.Fatalf("PreviousRate() error = %v, want no error", err)
+	}
+	want := &Rate{
+		TableNo: "074/A/NBP/2022",
+		Day:     date(2022, 4, 15),
+		Mid:     decimal.NewFromFloat(4.6378),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("PreviousRate() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNBP_NextRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    map[string]mockResponse
+		curr    Currency
+		day     time.Time
+		now     time.Time
+		want    *Rate
+		wantErr error
+	}{
+		{
+			name: "Go forward over a long weekend",
+			urls: map[string]mockResponse{
+				"A/EUR/2022-04-16": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+				"A/EUR/2022-04-17": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+				"A/EUR/2022-04-18": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+				"A/EUR/2022-04-19": {
+					rates: &nbpapi.Rates{
+						Table:    "A",
+						Currency: "euro",
+						Code:     "EUR",
+						Rates: []nbpapi.DailyRate{
+							{No: "075/A/NBP/2022", EffectiveDate: "2022-04-19", Mid: decimal.NewFromFloat(4.6448)},
+						},
+					},
+				},
+			},
+			curr: EUR,
+			day:  day(2022, 4, 15),
+			now:  time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC),
+			want: &Rate{
+				TableNo: "075/A/NBP/2022",
+				Day:     date(2022, 4, 19),
+				Mid:     decimal.NewFromFloat(4.6448),
+			},
+		},
+		{
+			name: "Don't go past today",
+			urls: map[string]mockResponse{
+				"A/EUR/2022-04-16": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+				"A/EUR/2022-04-17": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+			},
+			curr:    EUR,
+			day:     day(2022, 4, 15),
+			now:     time.Date(2022, 4, 17, 12, 0, 0, 0, time.UTC),
+			wantErr: ErrLookbackExceeded{Day: date(2022, 4, 15), Checked: 2, Last: date(2022, 4, 17)},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			n := testNBP(&mockClient{urls: tt.urls})
+			n.now = func() time.Time { return tt.now }
+			got, err := n.NextRate(tt.curr, tt.day)
+			if diff := cmp.Diff(tt.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("NextRate() error mismatch (-want +got):\n%s", diff)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("NextRate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNBP_Table(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    map[string]mockResponse
+		table   Table
+		day     time.Time
+		want    *ExchangeTable
+		wantErr error
+	}{
+		{
+			name: "Table A",
+			urls: map[string]mockResponse{
+				"A/2022-04-15": {
+					table: &nbpapi.ExchangeTable{
+						Table:         "A",
+						No:            "074/A/NBP/2022",
+						EffectiveDate: "2022-04-15",
+						Rates: []nbpapi.TableRate{
+							{Currency: "dolar amerykański", Code: "USD", Mid: decimal.NewFromFloat(4.2865)},
+							{Currency: "euro", Code: "EUR", Mid: decimal.NewFromFloat(4.6378)},
+						},
+					},
+				},
+			},
+			table: TableA,
+			day:   day(2022, 4, 15),
+			want: &ExchangeTable{
+				Table:   TableA,
+				TableNo: "074/A/NBP/2022",
+				Day:     date(2022, 4, 15),
+				Rates: []TableRate{
+					{Currency: USD, Name: "dolar amerykański", Mid: decimal.NewFromFloat(4.2865)},
+					{Currency: EUR, Name: "euro", Mid: decimal.NewFromFloat(4.6378)},
+				},
+			},
+		},
+		{
+			name: "Table C",
+			urls: map[string]mockResponse{
+				"C/2022-04-15": {
+					table: &nbpapi.ExchangeTable{
+						Table:         "C",
+						No:            "073/C/NBP/2022",
+						TradingDate:   "2022-04-14",
+						EffectiveDate: "2022-04-15",
+						Rates: []nbpapi.TableRate{
+							{Currency: "euro", Code: "EUR", Bid: decimal.NewFromFloat(4.5906), Ask: decimal.NewFromFloat(4.6834)},
+						},
+					},
+				},
+			},
+			table: TableC,
+			day:   day(2022, 4, 15),
+			want: &ExchangeTable{
+				Table:      TableC,
+				TableNo:    "073/C/NBP/2022",
+				TradingDay: date(2022, 4, 14),
+				Day:        date(2022, 4, 15),
+				Rates: []TableRate{
+					{Currency: EUR, Name: "euro", Bid: decimal.NewFromFloat(4.5906), Ask: decimal.NewFromFloat(4.6834)},
+				},
+			},
+		},
+		{
+			name: "No table for a given day",
+			urls: map[string]mockResponse{
+				"A/2022-04-16": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+			},
+			table:   TableA,
+			day:     day(2022, 4, 16),
+			wantErr: ErrNoExchangeRateForGivenDay,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			n := testNBP(&mockClient{urls: tt.urls}).WithTable(tt.table)
+			got, err := n.Table(tt.day)
+			if diff := cmp.Diff(tt.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Table() error mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Table() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExchangeTable_Find(t *testing.T) {
+	table := &ExchangeTable{Rates: []TableRate{
+		{Currency: USD, Mid: decimal.NewFromFloat(4.2865)},
+		{Currency: EUR, Mid: decimal.NewFromFloat(4.6378)},
+	}}
+	got, ok := table.Find(EUR)
+	if !ok || !got.Mid.Equal(decimal.NewFromFloat(4.6378)) {
+		t.Errorf("Find(EUR) = %v, %v, want EUR 4.6378", got, ok)
+	}
+	if _, ok := table.Find(CHF); ok {
+		t.Errorf("Find(CHF) found a rate, want none")
+	}
+}
+
+func TestNBP_LastRates(t *testing.T) {
+	eur := func(rates ...nbpapi.DailyRate) *nbpapi.Rates {
+		return &nbpapi.Rates{Table: "A", Currency: "euro", Code: "EUR", Rates: rates}
+	}
+	r15 := nbpapi.DailyRate{No: "074/A/NBP/2022", EffectiveDate: "2022-04-15", Mid: decimal.NewFromFloat(4.6378)}
+	r19 := nbpapi.DailyRate{No: "075/A/NBP/2022", EffectiveDate: "2022-04-19", Mid: decimal.NewFromFloat(4.6448)}
+	tests := []struct {
+		name    string
+		urls    map[string]mockResponse
+		table   Table
+		count   int
+		want    []Rate
+		wantErr bool
+	}{
+		{
+			name:  "Last two rates",
+			urls:  map[string]mockResponse{"A/EUR/last/2": {rates: eur(r15, r19)}},
+			table: TableA,
+			count: 2,
+			want: []Rate{
+				{TableNo: "074/A/NBP/2022", Day: date(2022, 4, 15), Mid: decimal.NewFromFloat(4.6378)},
+				{TableNo: "075/A/NBP/2022", Day: date(2022, 4, 19), Mid: decimal.NewFromFloat(4.6448)},
+			},
+		},
+		{
+			name:    "Zero rates",
+			table:   TableA,
+			count:   0,
+			wantErr: true,
+		},
+		{
+			name:    "Over the API limit",
+			table:   TableA,
+			count:   256,
+			wantErr: true,
+		},
+		{
+			name:    "Table C",
+			table:   TableC,
+			count:   2,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			n := testNBP(&mockClient{urls: tt.urls}).WithTable(tt.table)
+			got, err := n.LastRates(EUR, tt.count)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LastRates() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("LastRates() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNBP_TodayAndCurrent(t *testing.T) {
+	n := testNBP(&mockClient{urls: map[string]mockResponse{
+		"A/EUR/today": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+		"A/EUR": {rates: &nbpapi.Rates{Table: "A", Currency: "euro", Code: "EUR", Rates: []nbpapi.DailyRate{
+			{No: "075/A/NBP/2022", EffectiveDate: "2022-04-19", Mid: decimal.NewFromFloat(4.6448)},
+		}}},
+	}})
+	n.now = func() time.Time { return time.Date(2022, 4, 19, 15, 0, 0, 0, Warsaw) }
+
+	_, err := n.Today(EUR)
+	if diff := cmp.Diff(ErrNoExchangeRateForGivenDay, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Today() error mismatch (-want +got):\n%s", diff)
+	}
+
+	want := &Rate{TableNo: "075/A/NBP/2022", Day: date(2022, 4, 19), Mid: decimal.NewFromFloat(4.6448)}
+	got, err := n.Current(EUR)
+	if err != nil {
+		t.Errorf("Current() error = %v, want no error", err)
+		return
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Current() mismatch (-want +got):\n%s", diff)
+	}
+}
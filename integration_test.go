@@ -1,6 +1,7 @@
 package gonbp
 
 import (
+	"errors"
 	"github.com/google/go-cmp/cmp"
 	"github.com/igor-kupczynski/gonbp/internal/nbpapi"
 	"github.com/shopspring/decimal"
@@ -27,7 +28,7 @@ func TestIntegrationRate(t *testing.T) {
 		// {"table":"A","currency":"dolar amerykański","code":"USD","rates":[{"no":"043/A/NBP/2022","effectiveDate":"2022-03-03","mid":4.3257}]}
 		want := &Rate{
 			TableNo: "043/A/NBP/2022",
-			Day:     day(2022, 3, 3),
+			Day:     date(2022, 3, 3),
 			Mid:     decimal.NewFromFloat(4.3257),
 		}
 		t1 := time.Now()
@@ -65,7 +66,7 @@ func TestIntegrationRate(t *testing.T) {
 		// {"table":"A","currency":"euro","code":"EUR","rates":[{"no":"021/A/NBP/2021","effectiveDate":"2021-02-02","mid":4.5025}]}
 		want := &Rate{
 			TableNo: "021/A/NBP/2021",
-			Day:     day(2021, 2, 2),
+			Day:     date(2021, 2, 2),
 			Mid:     decimal.NewFromFloat(4.5025),
 		}
 		got, err := nbp.Rate(EUR, day(2021, 2, 2))
@@ -90,11 +91,19 @@ func TestIntegrationRate(t *testing.T) {
 	t.Run("Non-existing currency", func(t *testing.T) {
 		// 404 NotFound
 		wantErr := nbpapi.ErrNoRatesForCurrency
-		_, gotErr := nbp.Rate("DOGE", day(2022, 4, 15))
+		_, gotErr := nbp.Rate("BOV", day(2022, 4, 15))
 		if gotErr != wantErr {
 			t.Errorf("Rate() error = %v, want %v", gotErr, wantErr)
 		}
 	})
+
+	t.Run("Non-ISO currency", func(t *testing.T) {
+		wantErr := ErrInvalidCurrency
+		_, gotErr := nbp.Rate("DOGE", day(2022, 4, 15))
+		if !errors.Is(gotErr, wantErr) {
+			t.Errorf("Rate() error = %v, want %v", gotErr, wantErr)
+		}
+	})
 }
 
 func TestIntegrationPreviousRate(t *testing.T) {
@@ -110,7 +119,7 @@ func TestIntegrationPreviousRate(t *testing.T) {
 		// {"table":"A","currency":"dolar amerykański","code":"USD","rates":[{"no":"074/A/NBP/2022","effectiveDate":"2022-04-15","mid":4.2865}]}
 		want := &Rate{
 			TableNo: "074/A/NBP/2022",
-			Day:     day(2022, 4, 15),
+			Day:     date(2022, 4, 15),
 			Mid:     decimal.NewFromFloat(4.2865),
 		}
 		got, err := nbp.PreviousRate(USD, day(2022, 4, 18))
@@ -126,10 +135,138 @@ func TestIntegrationPreviousRate(t *testing.T) {
 	t.Run("Non-existing currency", func(t *testing.T) {
 		// 404 NotFound
 		wantErr := nbpapi.ErrNoRatesForCurrency
-		_, gotErr := nbp.Rate("DOGE", day(2022, 4, 16))
+		_, gotErr := nbp.Rate("BOV", day(2022, 4, 16))
 		if gotErr != wantErr {
 			t.Errorf("Rate() error = %v, want %v", gotErr, wantErr)
 		}
 	})
 
 }
+
+func TestIntegrationRateRange(t *testing.T) {
+	base, err := ioutil.TempDir("", "gonbp-integration test")
+	if err != nil {
+		t.Fatalf("Can't create the temp dir: %v", err)
+		return
+	}
+	defer os.RemoveAll(base)
+	nbp := Init(base, http.DefaultClient)
+
+	t.Run("EUR over a long weekend", func(t *testing.T) {
+		want := []Rate{
+			{TableNo: "074/A/NBP/2022", Day: date(2022, 4, 15), Mid: decimal.NewFromFloat(4.6378)},
+		}
+		got, err := nbp.RateRange(EUR, day(2022, 4, 15), day(2022, 4, 18))
+		if err != nil {
+			t.Errorf("RateRange() error = %v, want no error", err)
+			return
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("RateRange() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Range is cached per day", func(t *testing.T) {
+		wantErr := nbpapi.ErrNoExchangeRateForGivenDay
+		_, gotErr := nbp.Rate(EUR, day(2022, 4, 18))
+		if gotErr != wantErr {
+			t.Errorf("Rate() error = %v, want %v", gotErr, wantErr)
+		}
+	})
+}
+
+func TestIntegrationTable(t *testing.T) {
+	base, err := ioutil.TempDir("", "gonbp-integration test")
+	if err != nil {
+		t.Fatalf("Can't create the temp dir: %v", err)
+		return
+	}
+	defer os.RemoveAll(base)
+	nbp := Init(base, http.DefaultClient)
+
+	t.Run("Table A", func(t *testing.T) {
+		got, err := nbp.Table(day(2022, 4, 15))
+		if err != nil {
+			t.Errorf("Table() error = %v, want no error", err)
+			return
+		}
+		if got.TableNo != "074/A/NBP/2022" {
+			t.Errorf("Table() TableNo = %s, want 074/A/NBP/2022", got.TableNo)
+		}
+		eur, ok := got.Find(EUR)
+		if !ok || !eur.Mid.Equal(decimal.NewFromFloat(4.6378)) {
+			t.Errorf("Table() EUR = %v, want 4.6378", eur)
+		}
+	})
+
+	t.Run("Table fills the per-currency cache", func(t *testing.T) {
+		want := &Rate{TableNo: "074/A/NBP/2022", Day: date(2022, 4, 15), Mid: decimal.NewFromFloat(4.6378)}
+		got, err := nbp.Rate(EUR, day(2022, 4, 15))
+		if err != nil {
+			t.Errorf("Rate() error = %v, want no error", err)
+			return
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Rate() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestIntegrationGold(t *testing.T) {
+	base, err := ioutil.TempDir("", "gonbp-integration test")
+	if err != nil {
+		t.Fatalf("Can't create the temp dir: %v", err)
+		return
+	}
+	defer os.RemoveAll(base)
+	nbp := Init(base, http.DefaultClient)
+
+	t.Run("Previous gold price over a long weekend", func(t *testing.T) {
+		got, err := nbp.PreviousGold(day(2022, 4, 18))
+		if err != nil {
+			t.Errorf("PreviousGold() error = %v, want no error", err)
+			return
+		}
+		if got.Day != date(2022, 4, 15) || !got.Price.IsPositive() {
+			t.Errorf("PreviousGold() = %v, want a positive price on 2022-04-15", got)
+		}
+	})
+
+	t.Run("Gold range matches the daily prices", func(t *testing.T) {
+		got, err := nbp.GoldRange(day(2022, 4, 14), day(2022, 4, 19))
+		if err != nil {
+			t.Errorf("GoldRange() error = %v, want no error", err)
+			return
+		}
+		if len(got) != 3 {
+			t.Errorf("GoldRange() = %v, want prices for 3 days", got)
+		}
+	})
+}
+
+func TestIntegrationCurrencies(t *testing.T) {
+	base, err := ioutil.TempDir("", "gonbp-integration test")
+	if err != nil {
+		t.Fatalf("Can't create the temp dir: %v", err)
+		return
+	}
+	defer os.RemoveAll(base)
+	nbp := Init(base, http.DefaultClient)
+
+	for _, tt := range []struct {
+		curr  Currency
+		table Table
+	}{
+		{curr: EUR, table: TableA},
+		{curr: "AFN", table: TableB},
+	} {
+		got, err := nbp.LookupCurrency(tt.curr)
+		if err != nil {
+			t.Errorf("LookupCurrency(%s) error = %v, want no error", tt.curr, err)
+			continue
+		}
+		if got.Table != tt.table {
+			t.Errorf("LookupCurrency(%s) table = %s, want %s", tt.curr, got.Table, tt.table)
+		}
+	}
+}
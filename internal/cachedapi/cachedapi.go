@@ -1,111 +1,521 @@
 package cachedapi
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
+	"github.com/igor-kupczynski/gonbp/cache"
 	"github.com/igor-kupczynski/gonbp/internal/nbpapi"
-	"io/fs"
-	"io/ioutil"
-	"net/http"
-	"os"
+	"github.com/igor-kupczynski/gonbp/internal/publication"
 	"path"
+	"sync"
 	"time"
 )
 
 type nbpAPIClient interface {
-	Get(curr string, day time.Time) (*nbpapi.Rates, error)
+	Get(ctx context.Context, table nbpapi.Table, curr string, day time.Time) (*nbpapi.Rates, error)
+	GetRange(ctx context.Context, table nbpapi.Table, curr string, from, to time.Time) (*nbpapi.Rates, error)
+	GetLast(ctx context.Context, table nbpapi.Table, curr string, n int) (*nbpapi.Rates, error)
+	GetToday(ctx context.Context, table nbpapi.Table, curr string) (*nbpapi.Rates, error)
+	GetCurrent(ctx context.Context, table nbpapi.Table, curr string) (*nbpapi.Rates, error)
+	GetTable(ctx context.Context, table nbpapi.Table, day time.Time) (*nbpapi.ExchangeTable, error)
+	GetCurrentTable(ctx context.Context, table nbpapi.Table) (*nbpapi.ExchangeTable, error)
+	GetGold(ctx context.Context, day time.Time) (*nbpapi.GoldPrice, error)
+	GetGoldRange(ctx context.Context, from, to time.Time) ([]nbpapi.GoldPrice, error)
 }
 
 // Client is a low-level client over the NBP Rates API
 type Client struct {
-	dir string
-	api nbpAPIClient
+	cache cache.Cache
+	api   nbpAPIClient
+	now   func() time.Time
+
+	mu      sync.Mutex
+	flights map[string]*flight
 }
 
-// Init returns *Rates instance with net/http.DefaultClient
-func Init(cacheDir string, client *http.Client) *Client {
+// Init returns *Client instance storing the responses of a given api client in a given cache
+func Init(c cache.Cache, api *nbpapi.Client) *Client {
 	return &Client{
-		dir: cacheDir,
-		api: nbpapi.Init(client),
+		cache: c,
+		api:   api,
+		now:   time.Now,
 	}
 }
 
 type cacheKey struct {
-	curr string
-	day  time.Time
+	table nbpapi.Table
+	curr  string
+	day   time.Time
 }
 
-func (k *cacheKey) dir() string {
-	return k.curr
+func (k *cacheKey) String() string {
+	if k.day.IsZero() {
+		return path.Join(string(k.table), k.curr)
+	}
+	return path.Join(string(k.table), k.curr, k.day.Format("2006-01-02"))
 }
 
-func (k *cacheKey) fname() string {
-	return k.day.Format("2006-01-02.json")
-}
+const (
+	// allCurrencies is the currency part of the key under which the whole tables are cached
+	//
+	// It is lower-case, so that it never collides with a currency code, e.g. ALL is the Albanian lek.
+	allCurrencies = "_all"
+
+	// currentTable is the currency part of the key under which the most recent tables are cached, without a day
+	currentTable = "_current"
+
+	// currentTableTTL is how long the most recent table is served from the cache
+	currentTableTTL = 24 * time.Hour
+)
 
 type cacheValue struct {
-	Rates *nbpapi.Rates `json:"Rates,omitempty"`
+	Rates   *nbpapi.Rates         `json:"Rates,omitempty"`
+	Table   *nbpapi.ExchangeTable `json:"Table,omitempty"`
+	Gold    *nbpapi.GoldPrice     `json:"Gold,omitempty"`
+	Expires *time.Time            `json:"Expires,omitempty"`
+}
+
+func (v *cacheValue) expired(now time.Time) bool {
+	return v.Expires != nil && now.After(*v.Expires)
 }
 
 var noValueForDay = &cacheValue{Rates: nil}
 
-// Get returns the currency exchange rate for a given date from NBP table A
+// negativeRetryInterval is how long to wait before re-checking a day which is still not published past its deadline
+const negativeRetryInterval = time.Hour
+
+// noValue returns a negative cache entry for a given key
 //
-// Get first checks the on-disk cache and falls-back to nbpapi.Client.
-func (c *Client) Get(curr string, day time.Time) (*nbpapi.Rates, error) {
-	key := cacheKey{curr: curr, day: day}
-	v, err := c.get(key)
+// Entries for past days are permanent. Entries for today and future days expire at the day's publication deadline,
+// or after negativeRetryInterval if the deadline has already passed, in case the publication is late. Entries for
+// keys without a day expire after negativeRetryInterval.
+func (c *Client) noValue(k cacheKey) *cacheValue {
+	now := c.now()
+	if k.day.IsZero() {
+		expires := now.Add(negativeRetryInterval)
+		return &cacheValue{Rates: nil, Expires: &expires}
+	}
+	if k.day.Before(publication.Today(now)) {
+		return noValueForDay
+	}
+	expires := publication.Deadline(k.table, k.day)
+	if !now.Before(expires) {
+		expires = now.Add(negativeRetryInterval)
+	}
+	return &cacheValue{Rates: nil, Expires: &expires}
+}
 
-	if err == nil {
-		if v.Rates == nil {
-			return nil, nbpapi.ErrNoExchangeRateForGivenDay
+// Get returns the currency exchange rate for a given date from a given NBP table
+//
+// Get first checks the cache and falls-back to nbpapi.Client. Each table is cached under its own key prefix.
+// Concurrent cache misses for the same table, currency and day are coalesced into a single nbpapi.Client call.
+func (c *Client) Get(ctx context.Context, table nbpapi.Table, curr string, day time.Time) (*nbpapi.Rates, error) {
+	key := cacheKey{table: table, curr: curr, day: day}
+	v, err := c.getOrFetch(ctx, key, func(ctx context.Context) (*cacheValue, error) {
+		got, err := c.api.Get(ctx, key.table, key.curr, key.day)
+		if err != nil {
+			return nil, err
 		}
-		return v.Rates, nil
+		return &cacheValue{Rates: got}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if v.Rates == nil {
+		return nil, nbpapi.ErrNoExchangeRateForGivenDay
 	}
+	return v.Rates, nil
+}
 
-	var pathError *fs.PathError
-	if !errors.As(err, &pathError) {
+// GetTable returns a given NBP exchange rate table published for a given date
+//
+// GetTable first checks the cache and falls-back to nbpapi.Client. A table fetched from the API is also cached per
+// currency, so that the following Get calls for that table and day are served from the cache.
+func (c *Client) GetTable(ctx context.Context, table nbpapi.Table, day time.Time) (*nbpapi.ExchangeTable, error) {
+	key := cacheKey{table: table, curr: allCurrencies, day: day}
+	v, err := c.getOrFetch(ctx, key, func(ctx context.Context) (*cacheValue, error) {
+		got, err := c.api.GetTable(ctx, key.table, key.day)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.fillTable(table, day, got); err != nil {
+			return nil, err
+		}
+		return &cacheValue{Table: got}, nil
+	})
+	if err != nil {
 		return nil, err
 	}
+	if v.Table == nil {
+		return nil, nbpapi.ErrNoExchangeRateForGivenDay
+	}
+	return v.Table, nil
+}
 
-	got, err := c.api.Get(curr, day)
+// GetCurrentTable returns the most recently published NBP exchange rate table
+//
+// The table is served from the cache for currentTableTTL, so it suits the data which rarely changes, like the list
+// of currencies in the table. A table fetched from the API is also cached under its effective date, and per currency.
+func (c *Client) GetCurrentTable(ctx context.Context, table nbpapi.Table) (*nbpapi.ExchangeTable, error) {
+	key := cacheKey{table: table, curr: currentTable}
+	v, err := c.getOrFetch(ctx, key, func(ctx context.Context) (*cacheValue, error) {
+		got, err := c.api.GetCurrentTable(ctx, key.table)
+		if err != nil {
+			return nil, err
+		}
+		day, err := time.Parse("2006-01-02", got.EffectiveDate)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.fillTable(table, day, got); err != nil {
+			return nil, err
+		}
+		if err := c.set(cacheKey{table: table, curr: allCurrencies, day: day}, &cacheValue{Table: got}); err != nil {
+			return nil, err
+		}
+		expires := c.now().Add(currentTableTTL)
+		return &cacheValue{Table: got, Expires: &expires}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if v.Table == nil {
+		return nil, nbpapi.ErrNoExchangeRateForGivenDay
+	}
+	return v.Table, nil
+}
+
+// fillTable caches the rates from a given table per currency
+func (c *Client) fillTable(table nbpapi.Table, day time.Time, got *nbpapi.ExchangeTable) error {
+	for _, rate := range got.Rates {
+		v := &cacheValue{Rates: &nbpapi.Rates{
+			Table:    got.Table,
+			Currency: rate.Currency,
+			Code:     rate.Code,
+			Rates: []nbpapi.DailyRate{{
+				No:            got.No,
+				TradingDate:   got.TradingDate,
+				EffectiveDate: got.EffectiveDate,
+				Mid:           rate.Mid,
+				Bid:           rate.Bid,
+				Ask:           rate.Ask,
+			}},
+		}}
+		if err := c.set(cacheKey{table: table, curr: rate.Code, day: day}, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getOrFetch returns the cached value for a given key, or calls fetch and caches its result
+//
+// Concurrent cache misses for the same key are coalesced into a single fetch call, whose result is shared by all the
+// callers, see flight. A caller whose ctx is done stops waiting, and the fetch is cancelled once all of its callers
+// stopped waiting. ErrNoExchangeRateForGivenDay returned by fetch is cached as a negative entry.
+func (c *Client) getOrFetch(
+	ctx context.Context,
+	key cacheKey,
+	fetch func(ctx context.Context) (*cacheValue, error),
+) (*cacheValue, error) {
+	v, found, err := c.lookup(key)
+	if err != nil || found {
+		return v, err
+	}
+	f := c.join(ctx, key, fetch)
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		c.leave(key.String(), f)
+		return nil, ctx.Err()
+	}
+}
+
+// fetch calls fetchAPI and caches the result, unless a concurrent fetch has just cached it
+func (c *Client) fetch(
+	ctx context.Context,
+	key cacheKey,
+	fetchAPI func(ctx context.Context) (*cacheValue, error),
+) (*cacheValue, error) {
+	v, found, err := c.lookup(key)
+	if err != nil || found {
+		return v, err
+	}
+
+	v, err = fetchAPI(ctx)
 	if err == nbpapi.ErrNoExchangeRateForGivenDay {
-		if err := c.set(key, noValueForDay); err != nil {
+		v = c.noValue(key)
+	} else if err != nil {
+		return nil, err
+	}
+	if err := c.set(key, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// GetRange returns the currency exchange rates published between from and to (inclusive) in a given NBP table
+//
+// GetRange serves the period from the cache if all days are cached. Otherwise it fetches the uncached part
+// of the period with a single nbpapi.Client range query and caches every day from it, including the days without
+// publication.
+func (c *Client) GetRange(ctx context.Context, table nbpapi.Table, curr string, from, to time.Time) (*nbpapi.Rates, error) {
+	key := func(day time.Time) cacheKey {
+		return cacheKey{table: table, curr: curr, day: day}
+	}
+	values, err := c.getRange(from, to, key, func(from, to time.Time) (map[string]*cacheValue, error) {
+		got, err := c.api.GetRange(ctx, table, curr, from, to)
+		if err != nil {
 			return nil, err
 		}
+		return perDay(got), nil
+	})
+	if err != nil {
 		return nil, err
 	}
+
+	result := &nbpapi.Rates{Table: string(table), Code: curr, Rates: []nbpapi.DailyRate{}}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		v := values[day.Format("2006-01-02")]
+		if v.Rates == nil {
+			continue
+		}
+		result.Currency = v.Rates.Currency
+		result.Rates = append(result.Rates, v.Rates.Rates...)
+	}
+	return result, nil
+}
+
+// GetLast returns the last n currency exchange rates published in a given NBP table
+//
+// GetLast always calls nbpapi.Client, as the last rates change with every publication. The returned rates are cached
+// per day, including the days without publication between them.
+func (c *Client) GetLast(ctx context.Context, table nbpapi.Table, curr string, n int) (*nbpapi.Rates, error) {
+	got, err := c.api.GetLast(ctx, table, curr, n)
 	if err != nil {
 		return nil, err
 	}
-	if err := c.set(key, &cacheValue{got}); err != nil {
+	if err := c.fill(table, curr, got); err != nil {
+		return nil, err
+	}
+	return got, nil
+}
+
+// GetToday returns the currency exchange rate published today in a given NBP table
+//
+// GetToday is cached under today's date in Warsaw, the same way as Get for that day.
+func (c *Client) GetToday(ctx context.Context, table nbpapi.Table, curr string) (*nbpapi.Rates, error) {
+	key := cacheKey{table: table, curr: curr, day: publication.Today(c.now())}
+	v, err := c.getOrFetch(ctx, key, func(ctx context.Context) (*cacheValue, error) {
+		got, err := c.api.GetToday(ctx, key.table, key.curr)
+		if err != nil {
+			return nil, err
+		}
+		return &cacheValue{Rates: got}, nil
+	})
+	if err != nil {
 		return nil, err
 	}
+	if v.Rates == nil {
+		return nil, nbpapi.ErrNoExchangeRateForGivenDay
+	}
+	return v.Rates, nil
+}
 
+// GetCurrent returns the most recently published currency exchange rate in a given NBP table
+//
+// GetCurrent always calls nbpapi.Client and caches the returned rate under its effective date.
+func (c *Client) GetCurrent(ctx context.Context, table nbpapi.Table, curr string) (*nbpapi.Rates, error) {
+	got, err := c.api.GetCurrent(ctx, table, curr)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.fill(table, curr, got); err != nil {
+		return nil, err
+	}
 	return got, nil
 }
 
-func (c *Client) get(k cacheKey) (*cacheValue, error) {
-	dir := path.Join(c.dir, k.dir())
-	buf, err := ioutil.ReadFile(path.Join(dir, k.fname()))
+// fill caches the rates per day for every day between the first and the last of them
+//
+// The days without a rate in between are cached as negative entries.
+func (c *Client) fill(table nbpapi.Table, curr string, got *nbpapi.Rates) error {
+	if len(got.Rates) == 0 {
+		return nil
+	}
+	from, err := time.Parse("2006-01-02", got.Rates[0].EffectiveDate)
+	if err != nil {
+		return err
+	}
+	to, err := time.Parse("2006-01-02", got.Rates[len(got.Rates)-1].EffectiveDate)
+	if err != nil {
+		return err
+	}
+	published := perDay(got)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		key := cacheKey{table: table, curr: curr, day: day}
+		v, ok := published[day.Format("2006-01-02")]
+		if !ok {
+			v = c.noValue(key)
+		}
+		if err := c.set(key, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// perDay splits the rates into single day cache values keyed by the effective date
+func perDay(got *nbpapi.Rates) map[string]*cacheValue {
+	published := make(map[string]*cacheValue, len(got.Rates))
+	for _, rate := range got.Rates {
+		published[rate.EffectiveDate] = &cacheValue{Rates: &nbpapi.Rates{
+			Table:    got.Table,
+			Currency: got.Currency,
+			Code:     got.Code,
+			Rates:    []nbpapi.DailyRate{rate},
+		}}
+	}
+	return published
+}
+
+// gold is the table part of the key under which the gold prices are cached
+//
+// NBP publishes the gold prices along with table A, so the negative entries expire at the table A deadline.
+const gold nbpapi.Table = "GOLD"
+
+// GetGold returns the price of gold for a given date
+//
+// GetGold first checks the cache and falls-back to nbpapi.Client, the same way as Get.
+func (c *Client) GetGold(ctx context.Context, day time.Time) (*nbpapi.GoldPrice, error) {
+	key := cacheKey{table: gold, day: day}
+	v, err := c.getOrFetch(ctx, key, func(ctx context.Context) (*cacheValue, error) {
+		got, err := c.api.GetGold(ctx, key.day)
+		if err != nil {
+			return nil, err
+		}
+		return &cacheValue{Gold: got}, nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	if v.Gold == nil {
+		return nil, nbpapi.ErrNoExchangeRateForGivenDay
+	}
+	return v.Gold, nil
+}
+
+// GetGoldRange returns the gold prices published between from and to (inclusive)
+//
+// GetGoldRange caches the prices per day the same way as GetRange.
+func (c *Client) GetGoldRange(ctx context.Context, from, to time.Time) ([]nbpapi.GoldPrice, error) {
+	key := func(day time.Time) cacheKey {
+		return cacheKey{table: gold, day: day}
+	}
+	values, err := c.getRange(from, to, key, func(from, to time.Time) (map[string]*cacheValue, error) {
+		got, err := c.api.GetGoldRange(ctx, from, to)
+		if err != nil {
+			return nil, err
+		}
+		published := make(map[string]*cacheValue, len(got))
+		for i := range got {
+			published[got[i].Date] = &cacheValue{Gold: &got[i]}
+		}
+		return published, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []nbpapi.GoldPrice{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if v := values[day.Format("2006-01-02")]; v.Gold != nil {
+			result = append(result, *v.Gold)
+		}
+	}
+	return result, nil
+}
+
+// getRange returns the cached values for every day between from and to (inclusive), keyed by the formatted day
+//
+// Uncached days are fetched with a single fetch call spanning from the first to the last uncached day. fetch returns
+// the published values keyed by the formatted day, the remaining days are cached as negative entries.
+func (c *Client) getRange(
+	from, to time.Time,
+	key func(day time.Time) cacheKey,
+	fetch func(from, to time.Time) (map[string]*cacheValue, error),
+) (map[string]*cacheValue, error) {
+	cached := make(map[string]*cacheValue)
+	var missFrom, missTo time.Time
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		v, found, err := c.lookup(key(day))
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			cached[day.Format("2006-01-02")] = v
+			continue
+		}
+		if missFrom.IsZero() {
+			missFrom = day
+		}
+		missTo = day
+	}
+	if missFrom.IsZero() {
+		return cached, nil
+	}
+
+	published, err := fetch(missFrom, missTo)
+	if err != nil {
+		return nil, err
+	}
+	for day := missFrom; !day.After(missTo); day = day.AddDate(0, 0, 1) {
+		if _, ok := cached[day.Format("2006-01-02")]; ok {
+			continue
+		}
+		k := key(day)
+		v, ok := published[day.Format("2006-01-02")]
+		if !ok {
+			v = c.noValue(k)
+		}
+		if err := c.set(k, v); err != nil {
+			return nil, err
+		}
+		cached[day.Format("2006-01-02")] = v
+	}
+	return cached, nil
+}
+
+// lookup returns a cached value for a given key; expired and missing values are reported as not found
+func (c *Client) lookup(k cacheKey) (*cacheValue, bool, error) {
+	v, found, err := c.get(k)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	if v.expired(c.now()) {
+		return nil, false, nil
+	}
+	return v, true, nil
+}
+
+func (c *Client) get(k cacheKey) (*cacheValue, bool, error) {
+	buf, found, err := c.cache.Get(k.String())
+	if err != nil || !found {
+		return nil, false, err
+	}
 	var v cacheValue
 	if err := json.Unmarshal(buf, &v); err != nil {
-		return nil, err
+		// A corrupt entry is as good as a missing one, it gets overwritten with a fresh value
+		return nil, false, nil
 	}
-	return &v, nil
+	return &v, true, nil
 }
 
 func (c *Client) set(k cacheKey, v *cacheValue) error {
-	dir := path.Join(c.dir, k.dir())
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
-	}
 	buf, err := json.Marshal(v)
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(path.Join(dir, k.fname()), buf, 0644)
+	return c.cache.Set(k.String(), buf)
 }
@@ -1,22 +1,26 @@
 package cachedapi
 
 import (
-	"errors"
+	"context"
+	"fmt"
 	"github.com/google/go-cmp/cmp"
+	"github.com/igor-kupczynski/gonbp/cache"
 	"github.com/igor-kupczynski/gonbp/internal/nbpapi"
+	"github.com/igor-kupczynski/gonbp/internal/publication"
 	"github.com/shopspring/decimal"
-	"io/fs"
 	"io/ioutil"
 	"log"
 	"os"
+	"sync"
 	"testing"
 	"time"
 )
 
 func TestCacheGetSet(t *testing.T) {
 	key := cacheKey{
-		curr: "EUR",
-		day:  time.Date(2022, 4, 15, 0, 0, 0, 0, time.UTC),
+		table: nbpapi.TableA,
+		curr:  "EUR",
+		day:   time.Date(2022, 4, 15, 0, 0, 0, 0, time.UTC),
 	}
 	v := &cacheValue{Rates: &nbpapi.Rates{
 		Table:    "A",
@@ -38,19 +42,18 @@ func TestCacheGetSet(t *testing.T) {
 	defer os.Remove(base)
 
 	c := &Client{
-		dir: base,
-		api: nil,
+		cache: cache.NewFile(base),
+		api:   nil,
 	}
 
 	t.Run("read non-existing file", func(t *testing.T) {
-		got, err := c.get(key)
-		if got != nil {
+		got, found, err := c.get(key)
+		if got != nil || found {
 			t.Errorf("Expected nil value, got %v", got)
 			return
 		}
-		var pathError *fs.PathError
-		if !errors.As(err, &pathError) {
-			t.Errorf("Expected path error, got %v", err)
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
 			return
 		}
 	})
@@ -64,9 +67,9 @@ func TestCacheGetSet(t *testing.T) {
 	})
 
 	t.Run("read value previously set", func(t *testing.T) {
-		got, err := c.get(key)
-		if err != nil {
-			t.Errorf("Expected nil error, got %v", err)
+		got, found, err := c.get(key)
+		if err != nil || !found {
+			t.Errorf("Expected a cached value, got %v, %v", found, err)
 			return
 		}
 		if diff := cmp.Diff(v, got); diff != "" {
@@ -76,19 +79,844 @@ func TestCacheGetSet(t *testing.T) {
 	})
 
 	t.Run("read different value", func(t *testing.T) {
-		got, err := c.get(cacheKey{
-			curr: key.curr,
-			day:  key.day.AddDate(0, 0, -1),
+		got, found, err := c.get(cacheKey{
+			table: key.table,
+			curr:  key.curr,
+			day:   key.day.AddDate(0, 0, -1),
 		})
-		if got != nil {
+		if got != nil || found {
 			t.Errorf("Expected nil value, got %v", got)
 			return
 		}
-		var pathError *fs.PathError
-		if !errors.As(err, &pathError) {
-			t.Errorf("Expected path error, got %v", err)
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
 			return
 		}
 	})
 
+	t.Run("read same currency and day from a different table", func(t *testing.T) {
+		got, found, err := c.get(cacheKey{
+			table: nbpapi.TableC,
+			curr:  key.curr,
+			day:   key.day,
+		})
+		if got != nil || found {
+			t.Errorf("Expected nil value, got %v", got)
+			return
+		}
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+			return
+		}
+	})
+
+}
+
+type mockAPI struct {
+	days    map[string]*nbpapi.Rates
+	ranges  map[string]*nbpapi.Rates
+	latest  map[string]*nbpapi.Rates
+	tables  map[string]*nbpapi.ExchangeTable
+	current map[string]*nbpapi.ExchangeTable
+	gold    map[string][]nbpapi.GoldPrice
+	calls   []string
+}
+
+func (m *mockAPI) Get(ctx context.Context, table nbpapi.Table, curr string, day time.Time) (*nbpapi.Rates, error) {
+	url := fmt.Sprintf("%s/%s/%s", table, curr, day.Format("2006-01-02"))
+	m.calls = append(m.calls, url)
+	rates, ok := m.days[url]
+	if !ok {
+		panic("response not set up for " + url)
+	}
+	if rates == nil {
+		return nil, nbpapi.ErrNoExchangeRateForGivenDay
+	}
+	return rates, nil
+}
+
+func (m *mockAPI) GetRange(ctx context.Context, table nbpapi.Table, curr string, from, to time.Time) (*nbpapi.Rates, error) {
+	url := fmt.Sprintf("%s/%s/%s/%s", table, curr, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	m.calls = append(m.calls, url)
+	rates, ok := m.ranges[url]
+	if !ok {
+		panic("response not set up for " + url)
+	}
+	return rates, nil
+}
+
+func (m *mockAPI) GetLast(ctx context.Context, table nbpapi.Table, curr string, n int) (*nbpapi.Rates, error) {
+	return m.getLatest(fmt.Sprintf("%s/%s/last/%d", table, curr, n))
+}
+
+func (m *mockAPI) GetToday(ctx context.Context, table nbpapi.Table, curr string) (*nbpapi.Rates, error) {
+	return m.getLatest(fmt.Sprintf("%s/%s/today", table, curr))
+}
+
+func (m *mockAPI) GetCurrent(ctx context.Context, table nbpapi.Table, curr string) (*nbpapi.Rates, error) {
+	return m.getLatest(fmt.Sprintf("%s/%s", table, curr))
+}
+
+func (m *mockAPI) getLatest(url string) (*nbpapi.Rates, error) {
+	m.calls = append(m.calls, url)
+	rates, ok := m.latest[url]
+	if !ok {
+		panic("response not set up for " + url)
+	}
+	if rates == nil {
+		return nil, nbpapi.ErrNoExchangeRateForGivenDay
+	}
+	return rates, nil
+}
+
+func (m *mockAPI) GetTable(ctx context.Context, table nbpapi.Table, day time.Time) (*nbpapi.ExchangeTable, error) {
+	url := fmt.Sprintf("%s/%s", table, day.Format("2006-01-02"))
+	m.calls = append(m.calls, url)
+	t, ok := m.tables[url]
+	if !ok {
+		panic("response not set up for " + url)
+	}
+	if t == nil {
+		return nil, nbpapi.ErrNoExchangeRateForGivenDay
+	}
+	return t, nil
+}
+
+func (m *mockAPI) GetCurrentTable(ctx context.Context, table nbpapi.Table) (*nbpapi.ExchangeTable, error) {
+	url := string(table)
+	m.calls = append(m.calls, url)
+	t, ok := m.current[url]
+	if !ok {
+		panic("response not set up for " + url)
+	}
+	return t, nil
+}
+
+func (m *mockAPI) GetGold(ctx context.Context, day time.Time) (*nbpapi.GoldPrice, error) {
+	url := fmt.Sprintf("GOLD/%s", day.Format("2006-01-02"))
+	m.calls = append(m.calls, url)
+	prices, ok := m.gold[url]
+	if !ok {
+		panic("response not set up for " + url)
+	}
+	if len(prices) == 0 {
+		return nil, nbpapi.ErrNoExchangeRateForGivenDay
+	}
+	return &prices[0], nil
+}
+
+func (m *mockAPI) GetGoldRange(ctx context.Context, from, to time.Time) ([]nbpapi.GoldPrice, error) {
+	url := fmt.Sprintf("GOLD/%s/%s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	m.calls = append(m.calls, url)
+	prices, ok := m.gold[url]
+	if !ok {
+		panic("response not set up for " + url)
+	}
+	return prices, nil
+}
+
+func TestClient_GetRange(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2022, 4, d, 0, 0, 0, 0, time.UTC)
+	}
+	eur := func(rates ...nbpapi.DailyRate) *nbpapi.Rates {
+		return &nbpapi.Rates{Table: "A", Currency: "euro", Code: "EUR", Rates: rates}
+	}
+	r14 := nbpapi.DailyRate{No: "073/A/NBP/2022", EffectiveDate: "2022-04-14", Mid: decimal.NewFromFloat(4.6215)}
+	r15 := nbpapi.DailyRate{No: "074/A/NBP/2022", EffectiveDate: "2022-04-15", Mid: decimal.NewFromFloat(4.6378)}
+	r19 := nbpapi.DailyRate{No: "075/A/NBP/2022", EffectiveDate: "2022-04-19", Mid: decimal.NewFromFloat(4.6448)}
+
+	base, err := ioutil.TempDir("", "gonbp-TestClient_GetRange")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(base)
+
+	api := &mockAPI{ranges: map[string]*nbpapi.Rates{
+		"A/EUR/2022-04-15/2022-04-18": eur(r15),
+		"A/EUR/2022-04-14/2022-04-19": eur(r14, r19),
+	}}
+	c := &Client{
+		cache: cache.NewFile(base),
+		api:   api,
+		now:   time.Now,
+	}
+
+	t.Run("fetch and cache the whole period", func(t *testing.T) {
+		got, err := c.GetRange(context.Background(), nbpapi.TableA, "EUR", day(15), day(18))
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+			return
+		}
+		if diff := cmp.Diff(eur(r15), got); diff != "" {
+			t.Errorf("GetRange() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("days without publication are cached as negative entries", func(t *testing.T) {
+		got, found, err := c.get(cacheKey{table: nbpapi.TableA, curr: "EUR", day: day(17)})
+		if err != nil || !found {
+			t.Errorf("Expected a cached value, got %v, %v", found, err)
+			return
+		}
+		if diff := cmp.Diff(noValueForDay, got); diff != "" {
+			t.Errorf("get() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("per-day lookups are served from cache", func(t *testing.T) {
+		got, err := c.Get(context.Background(), nbpapi.TableA, "EUR", day(15))
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+			return
+		}
+		if diff := cmp.Diff(eur(r15), got); diff != "" {
+			t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+		}
+		_, err = c.Get(context.Background(), nbpapi.TableA, "EUR", day(16))
+		if err != nbpapi.ErrNoExchangeRateForGivenDay {
+			t.Errorf("Expected ErrNoExchangeRateForGivenDay, got %v", err)
+		}
+	})
+
+	t.Run("fetch only the uncached part of the period", func(t *testing.T) {
+		got, err := c.GetRange(context.Background(), nbpapi.TableA, "EUR", day(14), day(19))
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+			return
+		}
+		if diff := cmp.Diff(eur(r14, r15, r19), got); diff != "" {
+			t.Errorf("GetRange() mismatch (-want +got):\n%s", diff)
+		}
+		want := []string{"A/EUR/2022-04-15/2022-04-18", "A/EUR/2022-04-14/2022-04-19"}
+		if diff := cmp.Diff(want, api.calls); diff != "" {
+			t.Errorf("API calls mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("fully cached period doesn't call the API", func(t *testing.T) {
+		api.calls = nil
+		if _, err := c.GetRange(context.Background(), nbpapi.TableA, "EUR", day(14), day(19)); err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+			return
+		}
+		if len(api.calls) != 0 {
+			t.Errorf("Expected no API calls, got %v", api.calls)
+		}
+	})
+}
+
+func TestClient_GetNegativeExpiry(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(d int) time.Time {
+		return time.Date(2022, 4, d, 0, 0, 0, 0, time.UTC)
+	}
+	r15 := &nbpapi.Rates{Table: "A", Currency: "euro", Code: "EUR", Rates: []nbpapi.DailyRate{
+		{No: "074/A/NBP/2022", EffectiveDate: "2022-04-15", Mid: decimal.NewFromFloat(4.6378)},
+	}}
+
+	base, err := ioutil.TempDir("", "gonbp-TestClient_GetNegativeExpiry")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(base)
+
+	api := &mockAPI{days: map[string]*nbpapi.Rates{
+		"A/EUR/2022-04-14": nil,
+		"A/EUR/2022-04-15": nil,
+		"A/EUR/2022-04-18": nil,
+	}}
+	now := time.Date(2022, 4, 15, 9, 0, 0, 0, warsaw)
+	c := &Client{
+		cache: cache.NewFile(base),
+		api:   api,
+		now:   func() time.Time { return now },
+	}
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		day       time.Time
+		want      *nbpapi.Rates
+		wantCalls int
+	}{
+		{
+			name:      "today before publication is fetched",
+			now:       time.Date(2022, 4, 15, 9, 0, 0, 0, warsaw),
+			day:       day(15),
+			wantCalls: 1,
+		},
+		{
+			name:      "today before publication deadline is served from cache",
+			now:       time.Date(2022, 4, 15, 12, 0, 0, 0, warsaw),
+			day:       day(15),
+			wantCalls: 1,
+		},
+		{
+			name:      "future day is fetched",
+			now:       time.Date(2022, 4, 15, 12, 0, 0, 0, warsaw),
+			day:       day(18),
+			wantCalls: 2,
+		},
+		{
+			name:      "past day is fetched",
+			now:       time.Date(2022, 4, 15, 12, 0, 0, 0, warsaw),
+			day:       day(14),
+			wantCalls: 3,
+		},
+		{
+			name:      "today after publication deadline is fetched again",
+			now:       time.Date(2022, 4, 15, 12, 30, 0, 0, warsaw),
+			day:       day(15),
+			want:      r15,
+			wantCalls: 4,
+		},
+		{
+			name:      "past day negative entry is permanent",
+			now:       time.Date(2023, 4, 15, 12, 0, 0, 0, warsaw),
+			day:       day(14),
+			wantCalls: 4,
+		},
+		{
+			name:      "future day negative entry expires when the day becomes past",
+			now:       time.Date(2022, 4, 19, 12, 0, 0, 0, warsaw),
+			day:       day(18),
+			wantCalls: 5,
+		},
+		{
+			name:      "past day fetched after the day is over is permanent",
+			now:       time.Date(2023, 4, 19, 12, 0, 0, 0, warsaw),
+			day:       day(18),
+			wantCalls: 5,
+		},
+	}
+	for _, tt := range tests {
+		now = tt.now
+		if tt.want != nil {
+			api.days["A/EUR/"+tt.day.Format("2006-01-02")] = tt.want
+		}
+		got, err := c.Get(context.Background(), nbpapi.TableA, "EUR", tt.day)
+		if tt.want == nil && err != nbpapi.ErrNoExchangeRateForGivenDay {
+			t.Errorf("%s: expected ErrNoExchangeRateForGivenDay, got %v", tt.name, err)
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("%s: Get() mismatch (-want +got):\n%s", tt.name, diff)
+		}
+		if len(api.calls) != tt.wantCalls {
+			t.Errorf("%s: expected %d API calls, got %v", tt.name, tt.wantCalls, api.calls)
+		}
+	}
+}
+
+func TestClient_GetCorruptEntry(t *testing.T) {
+	r15 := &nbpapi.Rates{Table: "A", Currency: "euro", Code: "EUR", Rates: []nbpapi.DailyRate{
+		{No: "074/A/NBP/2022", EffectiveDate: "2022-04-15", Mid: decimal.NewFromFloat(4.6378)},
+	}}
+	store := cache.NewMemory(10)
+	if err := store.Set("A/EUR/2022-04-15", []byte(`{"Rates":{"table":"A","curr`)); err != nil {
+		t.Fatal(err)
+	}
+	api := &mockAPI{days: map[string]*nbpapi.Rates{"A/EUR/2022-04-15": r15}}
+	c := &Client{
+		cache: store,
+		api:   api,
+		now:   time.Now,
+	}
+
+	got, err := c.Get(context.Background(), nbpapi.TableA, "EUR", time.Date(2022, 4, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+		return
+	}
+	if diff := cmp.Diff(r15, got); diff != "" {
+		t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+	}
+	if len(api.calls) != 1 {
+		t.Errorf("Expected the corrupt entry to be re-fetched, got API calls %v", api.calls)
+	}
+	if _, found, err := c.get(cacheKey{table: nbpapi.TableA, curr: "EUR", day: time.Date(2022, 4, 15, 0, 0, 0, 0, time.UTC)}); err != nil || !found {
+		t.Errorf("Expected the entry to be overwritten, got %v, %v", found, err)
+	}
+}
+
+// blockingAPI counts the calls and blocks them until released, or until their ctx is done
+type blockingAPI struct {
+	mu        sync.Mutex
+	calls     int
+	cancelled int
+	release   chan struct{}
+	rates     *nbpapi.Rates
+}
+
+func (b *blockingAPI) Get(ctx context.Context, table nbpapi.Table, curr string, day time.Time) (*nbpapi.Rates, error) {
+	b.mu.Lock()
+	b.calls++
+	b.mu.Unlock()
+	select {
+	case <-b.release:
+		return b.rates, nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.cancelled++
+		b.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+func (b *blockingAPI) GetRange(ctx context.Context, table nbpapi.Table, curr string, from, to time.Time) (*nbpapi.Rates, error) {
+	panic("unexpected call to GetRange")
+}
+
+func (b *blockingAPI) GetLast(ctx context.Context, table nbpapi.Table, curr string, n int) (*nbpapi.Rates, error) {
+	panic("unexpected call to GetLast")
+}
+
+func (b *blockingAPI) GetToday(ctx context.Context, table nbpapi.Table, curr string) (*nbpapi.Rates, error) {
+	panic("unexpected call to GetToday")
+}
+
+func (b *blockingAPI) GetCurrent(ctx context.Context, table nbpapi.Table, curr string) (*nbpapi.Rates, error) {
+	panic("unexpected call to GetCurrent")
+}
+
+func (b *blockingAPI) GetTable(ctx context.Context, table nbpapi.Table, day time.Time) (*nbpapi.ExchangeTable, error) {
+	panic("unexpected call to GetTable")
+}
+
+func (b *blockingAPI) GetCurrentTable(ctx context.Context, table nbpapi.Table) (*nbpapi.ExchangeTable, error) {
+	panic("unexpected call to GetCurrentTable")
+}
+
+func (b *blockingAPI) GetGold(ctx context.Context, day time.Time) (*nbpapi.GoldPrice, error) {
+	panic("unexpected call to GetGold")
+}
+
+func (b *blockingAPI) GetGoldRange(ctx context.Context, from, to time.Time) ([]nbpapi.GoldPrice, error) {
+	panic("unexpected call to GetGoldRange")
+}
+
+// countingCache counts the writes
+type countingCache struct {
+	cache.Cache
+	mu   sync.Mutex
+	sets int
+}
+
+func (c *countingCache) Set(key string, value []byte) error {
+	c.mu.Lock()
+	c.sets++
+	c.mu.Unlock()
+	return c.Cache.Set(key, value)
+}
+
+func TestClient_GetConcurrent(t *testing.T) {
+	r15 := &nbpapi.Rates{Table: "A", Currency: "euro", Code: "EUR", Rates: []nbpapi.DailyRate{
+		{No: "074/A/NBP/2022", EffectiveDate: "2022-04-15", Mid: decimal.NewFromFloat(4.6378)},
+	}}
+	api := &blockingAPI{release: make(chan struct{}), rates: r15}
+	store := &countingCache{Cache: cache.NewMemory(10)}
+	c := &Client{
+		cache: store,
+		api:   api,
+		now:   time.Now,
+	}
+
+	const callers = 50
+	var started, done sync.WaitGroup
+	results := make([]*nbpapi.Rates, callers)
+	for i := 0; i < callers; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func(i int) {
+			defer done.Done()
+			started.Done()
+			got, err := c.Get(context.Background(), nbpapi.TableA, "EUR", time.Date(2022, 4, 15, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				t.Errorf("Expected nil error, got %v", err)
+			}
+			results[i] = got
+		}(i)
+	}
+	started.Wait()
+	time.Sleep(50 * time.Millisecond)
+	close(api.release)
+	done.Wait()
+
+	if api.calls != 1 {
+		t.Errorf("Expected a single API call, got %d", api.calls)
+	}
+	if store.sets != 1 {
+		t.Errorf("Expected a single cache write, got %d", store.sets)
+	}
+	for i, got := range results {
+		if diff := cmp.Diff(r15, got); diff != "" {
+			t.Errorf("Get() %d mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+func TestClient_GetTable(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2022, 4, d, 0, 0, 0, 0, time.UTC)
+	}
+	t15 := &nbpapi.ExchangeTable{
+		Table:         "A",
+		No:            "074/A/NBP/2022",
+		EffectiveDate: "2022-04-15",
+		Rates: []nbpapi.TableRate{
+			{Currency: "dolar amerykański", Code: "USD", Mid: decimal.NewFromFloat(4.2865)},
+			{Currency: "euro", Code: "EUR", Mid: decimal.NewFromFloat(4.6378)},
+		},
+	}
+	api := &mockAPI{tables: map[string]*nbpapi.ExchangeTable{
+		"A/2022-04-15": t15,
+		"A/2022-04-16": nil,
+	}}
+	c := &Client{
+		cache: cache.NewMemory(100),
+		api:   api,
+		now:   time.Now,
+	}
+
+	t.Run("fetch a table", func(t *testing.T) {
+		got, err := c.GetTable(context.Background(), nbpapi.TableA, day(15))
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+			return
+		}
+		if diff := cmp.Diff(t15, got); diff != "" {
+			t.Errorf("GetTable() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("table is served from cache", func(t *testing.T) {
+		if _, err := c.GetTable(context.Background(), nbpapi.TableA, day(15)); err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+			return
+		}
+		if len(api.calls) != 1 {
+			t.Errorf("Expected a single API call, got %v", api.calls)
+		}
+	})
+
+	t.Run("per-currency lookups are served from cache", func(t *testing.T) {
+		want := &nbpapi.Rates{
+			Table:    "A",
+			Currency: "euro",
+			Code:     "EUR",
+			Rates: []nbpapi.DailyRate{
+				{No: "074/A/NBP/2022", EffectiveDate: "2022-04-15", Mid: decimal.NewFromFloat(4.6378)},
+			},
+		}
+		got, err := c.Get(context.Background(), nbpapi.TableA, "EUR", day(15))
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+			return
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+		}
+		if len(api.calls) != 1 {
+			t.Errorf("Expected a single API call, got %v", api.calls)
+		}
+	})
+
+	t.Run("no table for a given day is cached", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			_, err := c.GetTable(context.Background(), nbpapi.TableA, day(16))
+			if err != nbpapi.ErrNoExchangeRateForGivenDay {
+				t.Errorf("Expected ErrNoExchangeRateForGivenDay, got %v", err)
+			}
+		}
+		if len(api.calls) != 2 {
+			t.Errorf("Expected two API calls, got %v", api.calls)
+		}
+	})
+}
+
+func TestClient_GetTableWithLek(t *testing.T) {
+	// ALL is the code of the Albanian lek in table B, the whole table must not overwrite its rate
+	day := time.Date(2022, 4, 13, 0, 0, 0, 0, time.UTC)
+	t13 := &nbpapi.ExchangeTable{
+		Table:         "B",
+		No:            "015/B/NBP/2022",
+		EffectiveDate: "2022-04-13",
+		Rates: []nbpapi.TableRate{
+			{Currency: "lek (Albania)", Code: "ALL", Mid: decimal.NewFromFloat(0.0388)},
+		},
+	}
+	lek := &nbpapi.Rates{Table: "B", Currency: "lek (Albania)", Code: "ALL", Rates: []nbpapi.DailyRate{
+		{No: "015/B/NBP/2022", EffectiveDate: "2022-04-13", Mid: decimal.NewFromFloat(0.0388)},
+	}}
+
+	t.Run("table first", func(t *testing.T) {
+		api := &mockAPI{tables: map[string]*nbpapi.ExchangeTable{"B/2022-04-13": t13}}
+		c := &Client{cache: cache.NewMemory(100), api: api, now: time.Now}
+		if _, err := c.GetTable(context.Background(), nbpapi.TableB, day); err != nil {
+			t.Fatalf("GetTable() error = %v, want no error", err)
+		}
+		got, err := c.Get(context.Background(), nbpapi.TableB, "ALL", day)
+		if err != nil {
+			t.Fatalf("Get() error = %v, want no error", err)
+		}
+		if diff := cmp.Diff(lek, got); diff != "" {
+			t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("rate first", func(t *testing.T) {
+		api := &mockAPI{
+			days:   map[string]*nbpapi.Rates{"B/ALL/2022-04-13": lek},
+			tables: map[string]*nbpapi.ExchangeTable{"B/2022-04-13": t13},
+		}
+		c := &Client{cache: cache.NewMemory(100), api: api, now: time.Now}
+		if _, err := c.Get(context.Background(), nbpapi.TableB, "ALL", day); err != nil {
+			t.Fatalf("Get() error = %v, want no error", err)
+		}
+		got, err := c.GetTable(context.Background(), nbpapi.TableB, day)
+		if err != nil {
+			t.Fatalf("GetTable() error = %v, want no error", err)
+		}
+		if diff := cmp.Diff(t13, got); diff != "" {
+			t.Errorf("GetTable() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("current table", func(t *testing.T) {
+		api := &mockAPI{current: map[string]*nbpapi.ExchangeTable{"B": t13}}
+		c := &Client{cache: cache.NewMemory(100), api: api, now: time.Now}
+		if _, err := c.GetCurrentTable(context.Background(), nbpapi.TableB); err != nil {
+			t.Fatalf("GetCurrentTable() error = %v, want no error", err)
+		}
+		got, err := c.Get(context.Background(), nbpapi.TableB, "ALL", day)
+		if err != nil {
+			t.Fatalf("Get() error = %v, want no error", err)
+		}
+		if diff := cmp.Diff(lek, got); diff != "" {
+			t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+		}
+		if _, err := c.GetTable(context.Background(), nbpapi.TableB, day); err != nil {
+			t.Errorf("GetTable() error = %v, want no error", err)
+		}
+		if len(api.calls) != 1 {
+			t.Errorf("Expected a single API call, got %v", api.calls)
+		}
+	})
+}
+
+func TestClient_GetGold(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2022, 4, d, 0, 0, 0, 0, time.UTC)
+	}
+	g14 := nbpapi.GoldPrice{Date: "2022-04-14", Price: decimal.NewFromFloat(267.31)}
+	g15 := nbpapi.GoldPrice{Date: "2022-04-15", Price: decimal.NewFromFloat(268.44)}
+	g19 := nbpapi.GoldPrice{Date: "2022-04-19", Price: decimal.NewFromFloat(269.02)}
+
+	api := &mockAPI{gold: map[string][]nbpapi.GoldPrice{
+		"GOLD/2022-04-15":            {g15},
+		"GOLD/2022-04-16":            {},
+		"GOLD/2022-04-14/2022-04-19": {g14, g19},
+	}}
+	c := &Client{
+		cache: cache.NewMemory(100),
+		api:   api,
+		now:   time.Now,
+	}
+
+	t.Run("fetch and cache a single day", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			got, err := c.GetGold(context.Background(), day(15))
+			if err != nil {
+				t.Errorf("Expected nil error, got %v", err)
+				return
+			}
+			if diff := cmp.Diff(&g15, got); diff != "" {
+				t.Errorf("GetGold() mismatch (-want +got):\n%s", diff)
+			}
+		}
+	})
+
+	t.Run("day without publication is cached as a negative entry", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			_, err := c.GetGold(context.Background(), day(16))
+			if err != nbpapi.ErrNoExchangeRateForGivenDay {
+				t.Errorf("Expected ErrNoExchangeRateForGivenDay, got %v", err)
+			}
+		}
+	})
+
+	t.Run("fetch only the uncached part of the period", func(t *testing.T) {
+		got, err := c.GetGoldRange(context.Background(), day(14), day(19))
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+			return
+		}
+		if diff := cmp.Diff([]nbpapi.GoldPrice{g14, g15, g19}, got); diff != "" {
+			t.Errorf("GetGoldRange() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("fully cached period doesn't call the API", func(t *testing.T) {
+		got, err := c.GetGoldRange(context.Background(), day(15), day(18))
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+			return
+		}
+		if diff := cmp.Diff([]nbpapi.GoldPrice{g15}, got); diff != "" {
+			t.Errorf("GetGoldRange() mismatch (-want +got):\n%s", diff)
+		}
+		want := []string{"GOLD/2022-04-15", "GOLD/2022-04-16", "GOLD/2022-04-14/2022-04-19"}
+		if diff := cmp.Diff(want, api.calls); diff != "" {
+			t.Errorf("API calls mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestClient_GetLatest(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2022, 4, d, 0, 0, 0, 0, time.UTC)
+	}
+	eur := func(rates ...nbpapi.DailyRate) *nbpapi.Rates {
+		return &nbpapi.Rates{Table: "A", Currency: "euro", Code: "EUR", Rates: rates}
+	}
+	r15 := nbpapi.DailyRate{No: "074/A/NBP/2022", EffectiveDate: "2022-04-15", Mid: decimal.NewFromFloat(4.6378)}
+	r19 := nbpapi.DailyRate{No: "075/A/NBP/2022", EffectiveDate: "2022-04-19", Mid: decimal.NewFromFloat(4.6448)}
+	r20 := nbpapi.DailyRate{No: "076/A/NBP/2022", EffectiveDate: "2022-04-20", Mid: decimal.NewFromFloat(4.6405)}
+
+	api := &mockAPI{latest: map[string]*nbpapi.Rates{
+		"A/EUR/last/2": eur(r15, r19),
+		"A/EUR":        eur(r19),
+		"A/EUR/today":  eur(r20),
+	}}
+	c := &Client{
+		cache: cache.NewMemory(100),
+		api:   api,
+		now: func() time.Time {
+			return time.Date(2022, 4, 20, 13, 0, 0, 0, publication.Warsaw)
+		},
+	}
+
+	t.Run("last rates fill the per-day cache", func(t *testing.T) {
+		got, err := c.GetLast(context.Background(), nbpapi.TableA, "EUR", 2)
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+			return
+		}
+		if diff := cmp.Diff(eur(r15, r19), got); diff != "" {
+			t.Errorf("GetLast() mismatch (-want +got):\n%s", diff)
+		}
+		got, err = c.Get(context.Background(), nbpapi.TableA, "EUR", day(15))
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+			return
+		}
+		if diff := cmp.Diff(eur(r15), got); diff != "" {
+			t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+		}
+		_, err = c.Get(context.Background(), nbpapi.TableA, "EUR", day(18))
+		if err != nbpapi.ErrNoExchangeRateForGivenDay {
+			t.Errorf("Expected ErrNoExchangeRateForGivenDay, got %v", err)
+		}
+	})
+
+	t.Run("current rate is always fetched", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			got, err := c.GetCurrent(context.Background(), nbpapi.TableA, "EUR")
+			if err != nil {
+				t.Errorf("Expected nil error, got %v", err)
+				return
+			}
+			if diff := cmp.Diff(eur(r19), got); diff != "" {
+				t.Errorf("GetCurrent() mismatch (-want +got):\n%s", diff)
+			}
+		}
+	})
+
+	t.Run("today's rate is cached under today's date", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			got, err := c.GetToday(context.Background(), nbpapi.TableA, "EUR")
+			if err != nil {
+				t.Errorf("Expected nil error, got %v", err)
+				return
+			}
+			if diff := cmp.Diff(eur(r20), got); diff != "" {
+				t.Errorf("GetToday() mismatch (-want +got):\n%s", diff)
+			}
+		}
+		got, err := c.Get(context.Background(), nbpapi.TableA, "EUR", day(20))
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+			return
+		}
+		if diff := cmp.Diff(eur(r20), got); diff != "" {
+			t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	want := []string{"A/EUR/last/2", "A/EUR", "A/EUR", "A/EUR/today"}
+	if diff := cmp.Diff(want, api.calls); diff != "" {
+		t.Errorf("API calls mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient_GetCurrentTable(t *testing.T) {
+	b15 := &nbpapi.ExchangeTable{
+		Table:         "B",
+		No:            "015/B/NBP/2022",
+		EffectiveDate: "2022-04-13",
+		Rates: []nbpapi.TableRate{
+			{Currency: "afgani (Afganistan)", Code: "AFN", Mid: decimal.NewFromFloat(0.048802)},
+		},
+	}
+	api := &mockAPI{current: map[string]*nbpapi.ExchangeTable{"B": b15}}
+	now := time.Date(2022, 4, 14, 10, 0, 0, 0, time.UTC)
+	c := &Client{
+		cache: cache.NewMemory(100),
+		api:   api,
+		now: func() time.Time {
+			return now
+		},
+	}
+
+	t.Run("current table is cached", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			got, err := c.GetCurrentTable(context.Background(), nbpapi.TableB)
+			if err != nil {
+				t.Errorf("Expected nil error, got %v", err)
+				return
+			}
+			if diff := cmp.Diff(b15, got); diff != "" {
+				t.Errorf("GetCurrentTable() mismatch (-want +got):\n%s", diff)
+			}
+		}
+		if len(api.calls) != 1 {
+			t.Errorf("Expected a single API call, got %v", api.calls)
+		}
+	})
+
+	t.Run("current table fills the dated cache", func(t *testing.T) {
+		day := time.Date(2022, 4, 13, 0, 0, 0, 0, time.UTC)
+		if _, err := c.GetTable(context.Background(), nbpapi.TableB, day); err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+		}
+		if _, err := c.Get(context.Background(), nbpapi.TableB, "AFN", day); err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+		}
+		if len(api.calls) != 1 {
+			t.Errorf("Expected a single API call, got %v", api.calls)
+		}
+	})
+
+	t.Run("current table expires", func(t *testing.T) {
+		now = now.Add(currentTableTTL + time.Minute)
+		if _, err := c.GetCurrentTable(context.Background(), nbpapi.TableB); err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+		}
+		if len(api.calls) != 2 {
+			t.Errorf("Expected two API calls, got %v", api.calls)
+		}
+	})
 }
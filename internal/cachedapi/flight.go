@@ -0,0 +1,84 @@
+package cachedapi
+
+import (
+	"context"
+	"time"
+)
+
+// flight is a single fetch shared by the concurrent getOrFetch callers with the same key
+//
+// The fetch runs with the values of the ctx of the caller which started it, but not with its cancellation, so that
+// it completes for the other callers. It is cancelled once the last of its callers stops waiting.
+type flight struct {
+	done    chan struct{}
+	value   *cacheValue
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// join returns the flight in progress for a given key, or starts a new one, and counts the caller as its waiter
+func (c *Client) join(ctx context.Context, key cacheKey, fetch func(ctx context.Context) (*cacheValue, error)) *flight {
+	k := key.String()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if f, ok := c.flights[k]; ok {
+		f.waiters++
+		return f
+	}
+
+	fetchCtx, cancel := context.WithCancel(detachedContext{parent: ctx})
+	f := &flight{done: make(chan struct{}), waiters: 1, cancel: cancel}
+	if c.flights == nil {
+		c.flights = make(map[string]*flight)
+	}
+	c.flights[k] = f
+	go func() {
+		f.value, f.err = c.fetch(fetchCtx, key, fetch)
+		cancel()
+		c.mu.Lock()
+		if c.flights[k] == f {
+			delete(c.flights, k)
+		}
+		c.mu.Unlock()
+		close(f.done)
+	}()
+	return f
+}
+
+// leave stops counting a caller as the waiter of a given flight, and cancels the flight if it was the last one
+//
+// The cancelled flight is forgotten right away, so that the following callers start a new one.
+func (c *Client) leave(k string, f *flight) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f.waiters--
+	if f.waiters > 0 {
+		return
+	}
+	f.cancel()
+	if c.flights[k] == f {
+		delete(c.flights, k)
+	}
+}
+
+// detachedContext keeps the values of its parent context, but is never cancelled and has no deadline
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key any) any {
+	return d.parent.Value(key)
+}
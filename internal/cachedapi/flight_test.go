@@ -0,0 +1,127 @@
+package cachedapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/igor-kupczynski/gonbp/cache"
+	"github.com/igor-kupczynski/gonbp/internal/nbpapi"
+	"github.com/shopspring/decimal"
+)
+
+// eventually polls cond until it holds, and fails the test if it doesn't within a second
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// waiters returns the number of callers waiting for the flight with a given key
+func (c *Client) waiters(k cacheKey) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if f, ok := c.flights[k.String()]; ok {
+		return f.waiters
+	}
+	return 0
+}
+
+func (b *blockingAPI) counts() (calls, cancelled int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.calls, b.cancelled
+}
+
+func TestClient_GetFlightCancelled(t *testing.T) {
+	r15 := &nbpapi.Rates{Table: "A", Currency: "euro", Code: "EUR", Rates: []nbpapi.DailyRate{
+		{No: "074/A/NBP/2022", EffectiveDate: "2022-04-15", Mid: decimal.NewFromFloat(4.6378)},
+	}}
+	day := time.Date(2022, 4, 15, 0, 0, 0, 0, time.UTC)
+	key := cacheKey{table: nbpapi.TableA, curr: "EUR", day: day}
+
+	type result struct {
+		rates *nbpapi.Rates
+		err   error
+	}
+	// get calls Get in the background and waits until the caller joins the flight
+	get := func(t *testing.T, c *Client, ctx context.Context) <-chan result {
+		t.Helper()
+		joined := c.waiters(key) + 1
+		ch := make(chan result, 1)
+		go func() {
+			got, err := c.Get(ctx, nbpapi.TableA, "EUR", day)
+			ch <- result{got, err}
+		}()
+		eventually(t, "the caller to join the flight", func() bool { return c.waiters(key) == joined })
+		return ch
+	}
+
+	t.Run("fetch continues while any caller waits", func(t *testing.T) {
+		api := &blockingAPI{release: make(chan struct{}), rates: r15}
+		c := &Client{cache: cache.NewMemory(10), api: api, now: time.Now}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		first := get(t, c, ctx)
+		second := get(t, c, context.Background())
+
+		cancel()
+		if got := <-first; got.err != context.Canceled {
+			t.Errorf("Expected %v for the cancelled caller, got %v", context.Canceled, got.err)
+		}
+		close(api.release)
+		got := <-second
+		if got.err != nil {
+			t.Fatalf("Expected nil error, got %v", got.err)
+		}
+		if diff := cmp.Diff(r15, got.rates); diff != "" {
+			t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+		}
+		if calls, cancelled := api.counts(); calls != 1 || cancelled != 0 {
+			t.Errorf("Expected a single API call which is not cancelled, got %d calls, %d cancelled", calls, cancelled)
+		}
+	})
+
+	t.Run("fetch is cancelled once all callers stopped waiting", func(t *testing.T) {
+		api := &blockingAPI{release: make(chan struct{}), rates: r15}
+		c := &Client{cache: cache.NewMemory(10), api: api, now: time.Now}
+
+		ctx1, cancel1 := context.WithCancel(context.Background())
+		ctx2, cancel2 := context.WithCancel(context.Background())
+		first := get(t, c, ctx1)
+		second := get(t, c, ctx2)
+
+		cancel1()
+		<-first
+		if _, cancelled := api.counts(); cancelled != 0 {
+			t.Errorf("Expected the fetch to continue for the second caller")
+		}
+		cancel2()
+		if got := <-second; got.err != context.Canceled {
+			t.Errorf("Expected %v for the cancelled caller, got %v", context.Canceled, got.err)
+		}
+		eventually(t, "the fetch to be cancelled", func() bool {
+			_, cancelled := api.counts()
+			return cancelled == 1
+		})
+
+		// The following caller starts a new fetch
+		close(api.release)
+		got, err := c.Get(context.Background(), nbpapi.TableA, "EUR", day)
+		if err != nil {
+			t.Fatalf("Expected nil error, got %v", err)
+		}
+		if diff := cmp.Diff(r15, got); diff != "" {
+			t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+		}
+		if calls, _ := api.counts(); calls != 2 {
+			t.Errorf("Expected a second API call, got %d calls", calls)
+		}
+	})
+}
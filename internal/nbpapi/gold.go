@@ -0,0 +1,59 @@
+package nbpapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	goldBase = "https://api.nbp.pl/api/cenyzlota"
+
+	// MaxGoldRangeDays is the longest period NBP API allows in a single gold price range query
+	MaxGoldRangeDays = 367
+)
+
+// GoldPrice represents the price of 1 g of gold for a single day as returned by the NBP gold prices API
+type GoldPrice struct {
+	Date  string          `json:"data"`
+	Price decimal.Decimal `json:"cena"`
+}
+
+// GetGold returns the price of gold for a given date
+func (c *Client) GetGold(ctx context.Context, day time.Time) (*GoldPrice, error) {
+	var prices []GoldPrice
+	if err := c.get(ctx, fmt.Sprintf("%s/%s", goldBase, day.Format("2006-01-02")), &prices); err != nil {
+		return nil, err
+	}
+	if len(prices) != 1 {
+		return nil, fmt.Errorf("expectation failed: wanted a single gold price, instead got %d", len(prices))
+	}
+	return &prices[0], nil
+}
+
+// GetGoldRange returns the gold prices published between from and to (inclusive)
+//
+// Periods longer than MaxGoldRangeDays are split into multiple API calls and the results are merged in order. Days
+// without publication are absent from the result. If there are no prices in the whole period GetGoldRange returns
+// an empty slice.
+func (c *Client) GetGoldRange(ctx context.Context, from, to time.Time) ([]GoldPrice, error) {
+	result := []GoldPrice{}
+	for start := from; !start.After(to); start = start.AddDate(0, 0, MaxGoldRangeDays) {
+		end := start.AddDate(0, 0, MaxGoldRangeDays-1)
+		if end.After(to) {
+			end = to
+		}
+		var prices []GoldPrice
+		err := c.get(ctx, fmt.Sprintf("%s/%s/%s", goldBase, start.Format("2006-01-02"), end.Format("2006-01-02")), &prices)
+		if err == ErrNoExchangeRateForGivenDay {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, prices...)
+	}
+	return result, nil
+}
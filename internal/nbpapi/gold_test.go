@@ -0,0 +1,136 @@
+package nbpapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+func TestClient_GetGold(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    map[string]mockResponse
+		day     time.Time
+		want    *GoldPrice
+		wantErr bool
+	}{
+		{
+			name: "Positive case",
+			urls: map[string]mockResponse{
+				"https://api.nbp.pl/api/cenyzlota/2022-04-15": {
+					code: 200,
+					body: `[{"data":"2022-04-15","cena":268.44}]`,
+				},
+			},
+			day:     day(2022, 4, 15),
+			want:    &GoldPrice{Date: "2022-04-15", Price: decimal.NewFromFloat(268.44)},
+			wantErr: false,
+		},
+		{
+			name: "Not found for a given day",
+			urls: map[string]mockResponse{
+				"https://api.nbp.pl/api/cenyzlota/2022-04-16": {
+					code: 404,
+					body: `404 NotFound - Not Found - Brak danych`,
+				},
+			},
+			day:     day(2022, 4, 16),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := Init(&mockClient{urls: tt.urls})
+			got, err := c.GetGold(context.Background(), tt.day)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetGold() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetGold() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_GetGoldRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    map[string]mockResponse
+		from    time.Time
+		to      time.Time
+		want    []GoldPrice
+		wantErr bool
+	}{
+		{
+			name: "Single request",
+			urls: map[string]mockResponse{
+				"https://api.nbp.pl/api/cenyzlota/2022-04-14/2022-04-19": {
+					code: 200,
+					body: `[{"data":"2022-04-14","cena":267.31},{"data":"2022-04-15","cena":268.44},{"data":"2022-04-19","cena":269.02}]`,
+				},
+			},
+			from: day(2022, 4, 14),
+			to:   day(2022, 4, 19),
+			want: []GoldPrice{
+				{Date: "2022-04-14", Price: decimal.NewFromFloat(267.31)},
+				{Date: "2022-04-15", Price: decimal.NewFromFloat(268.44)},
+				{Date: "2022-04-19", Price: decimal.NewFromFloat(269.02)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Split at 367 days",
+			urls: map[string]mockResponse{
+				"https://api.nbp.pl/api/cenyzlota/2021-01-01/2022-01-02": {
+					code: 200,
+					body: `[{"data":"2021-01-04","cena":222.04},{"data":"2021-12-31","cena":236.83}]`,
+				},
+				"https://api.nbp.pl/api/cenyzlota/2022-01-03/2022-01-04": {
+					code: 200,
+					body: `[{"data":"2022-01-03","cena":238.52},{"data":"2022-01-04","cena":236.61}]`,
+				},
+			},
+			from: day(2021, 1, 1),
+			to:   day(2022, 1, 4),
+			want: []GoldPrice{
+				{Date: "2021-01-04", Price: decimal.NewFromFloat(222.04)},
+				{Date: "2021-12-31", Price: decimal.NewFromFloat(236.83)},
+				{Date: "2022-01-03", Price: decimal.NewFromFloat(238.52)},
+				{Date: "2022-01-04", Price: decimal.NewFromFloat(236.61)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "No prices in the period",
+			urls: map[string]mockResponse{
+				"https://api.nbp.pl/api/cenyzlota/2022-04-16/2022-04-18": {
+					code: 404,
+					body: `404 NotFound - Not Found - Brak danych`,
+				},
+			},
+			from:    day(2022, 4, 16),
+			to:      day(2022, 4, 18),
+			want:    []GoldPrice{},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := Init(&mockClient{urls: tt.urls})
+			got, err := c.GetGoldRange(context.Background(), tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetGoldRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetGoldRange() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package nbpapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// concurrencyClient records the maximum number of concurrent calls
+type concurrencyClient struct {
+	mu      sync.Mutex
+	current int
+	max     int
+	calls   int
+}
+
+func (c *concurrencyClient) Do(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	c.current++
+	c.calls++
+	if c.current > c.max {
+		c.max = c.current
+	}
+	c.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	c.mu.Lock()
+	c.current--
+	c.mu.Unlock()
+	return &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(strings.NewReader(eurBody)),
+	}, nil
+}
+
+func TestClient_MaxInFlight(t *testing.T) {
+	http := &concurrencyClient{}
+	c := Init(http, WithMaxInFlight(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Get(context.Background(), TableA, "EUR", day(2022, 4, 15)); err != nil {
+				t.Errorf("Get() error = %v, want no error", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if http.calls != 10 {
+		t.Errorf("Get() made %d calls, want 10", http.calls)
+	}
+	if http.max > 2 {
+		t.Errorf("Get() made %d concurrent calls, want at most 2", http.max)
+	}
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	http := &concurrencyClient{}
+	c := Init(http, WithRateLimit(0.001, 1))
+
+	if _, err := c.Get(context.Background(), TableA, "EUR", day(2022, 4, 15)); err != nil {
+		t.Errorf("Get() error = %v, want no error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.Get(ctx, TableA, "EUR", day(2022, 4, 15)); err == nil {
+		t.Errorf("Get() error = nil, want the rate limit to exceed the deadline")
+	}
+	if http.calls != 1 {
+		t.Errorf("Get() made %d calls, want 1", http.calls)
+	}
+}
+
+func TestClient_NoLimits(t *testing.T) {
+	c := Init(&concurrencyClient{}, WithRateLimit(0, 0), WithMaxInFlight(0))
+	if c.limiter != nil || c.inFlight != nil {
+		t.Errorf("Expected no limits, got limiter %v and in-flight %v", c.limiter, c.inFlight)
+	}
+}
@@ -2,31 +2,61 @@ package nbpapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/shopspring/decimal"
+	"golang.org/x/time/rate"
 	"io"
 	"net/http"
 	"time"
 )
 
 type httpClient interface {
-	Get(url string) (resp *http.Response, err error)
+	Do(req *http.Request) (*http.Response, error)
 }
 
 // Client is a low-level client over the NBP rates API
 type Client struct {
-	http httpClient
+	http     httpClient
+	retry    RetryPolicy
+	sleep    func(ctx context.Context, d time.Duration) error
+	limiter  *rate.Limiter
+	inFlight chan struct{}
 }
 
-// Init returns *Rates instance with net/http.DefaultClient
-func Init(client httpClient) *Client {
-	return &Client{
-		http: client,
+// Option configures *Client instance
+type Option func(*Client)
+
+// Init returns *Client instance with a given httpClient
+//
+// Failed calls are retried according to DefaultRetryPolicy, unless a different policy is set with WithRetryPolicy.
+// The calls are not rate limited, unless configured with WithRateLimit and WithMaxInFlight.
+func Init(client httpClient, opts ...Option) *Client {
+	c := &Client{
+		http:  client,
+		retry: DefaultRetryPolicy,
+		sleep: sleep,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
+// Table enumerates the NBP exchange rate tables
+type Table string
+
+const (
+	// TableA contains the mid rates of the most traded currencies, published every working day
+	TableA Table = "A"
+	// TableB contains the mid rates of the remaining currencies, published weekly on Wednesdays
+	TableB Table = "B"
+	// TableC contains the buy (bid) and sell (ask) rates, published every working day
+	TableC Table = "C"
+)
+
 // Rates represents the return value of the NBP rates API
 type Rates struct {
 	Table    string      `json:"table"`
@@ -36,10 +66,15 @@ type Rates struct {
 }
 
 // DailyRate represent a rate for a single day
+//
+// Tables A and B publish the Mid rate, table C publishes the Bid and Ask rates and the TradingDate.
 type DailyRate struct {
 	No            string          `json:"no"`
+	TradingDate   string          `json:"tradingDate,omitempty"`
 	EffectiveDate string          `json:"effectiveDate"`
 	Mid           decimal.Decimal `json:"mid"`
+	Bid           decimal.Decimal `json:"bid"`
+	Ask           decimal.Decimal `json:"ask"`
 }
 
 // ErrNoExchangeRateForGivenDay represents a failure where there are no published rates for a given day
@@ -52,6 +87,8 @@ var ErrNoRatesForCurrency = errors.New("currency without published rates")
 type ErrApiCallUnsuccessful struct {
 	Code int
 	Body string
+	// RetryAfter is the delay requested by the server in the Retry-After header, if any
+	RetryAfter time.Duration
 }
 
 func (e ErrApiCallUnsuccessful) Error() string {
@@ -59,38 +96,132 @@ func (e ErrApiCallUnsuccessful) Error() string {
 }
 
 const (
-	apiBase = "https://api.nbp.pl/api/exchangerates/rates/A"
+	apiBase = "https://api.nbp.pl/api/exchangerates/rates"
+
+	// MaxRangeDays is the longest period NBP API allows in a single date range query
+	MaxRangeDays = 93
+
+	// MaxLast is the largest number of the last rates NBP API returns in a single query
+	MaxLast = 255
 )
 
-// Get returns the currency exchange rate for a given date from NBP table A
-func (c *Client) Get(curr string, day time.Time) (*Rates, error) {
-	resp, err := c.http.Get(fmt.Sprintf("%s/%s/%s", apiBase, curr, day.Format("2006-01-02")))
+// Get returns the currency exchange rate for a given date from a given NBP table
+func (c *Client) Get(ctx context.Context, table Table, curr string, day time.Time) (*Rates, error) {
+	var rates Rates
+	if err := c.get(ctx, fmt.Sprintf("%s/%s/%s/%s", apiBase, table, curr, day.Format("2006-01-02")), &rates); err != nil {
+		return nil, err
+	}
+	return &rates, nil
+}
+
+// GetLast returns the last n currency exchange rates published in a given NBP table, ordered by day
+func (c *Client) GetLast(ctx context.Context, table Table, curr string, n int) (*Rates, error) {
+	var rates Rates
+	if err := c.get(ctx, fmt.Sprintf("%s/%s/%s/last/%d", apiBase, table, curr, n), &rates); err != nil {
+		return nil, err
+	}
+	return &rates, nil
+}
+
+// GetToday returns the currency exchange rate published today in a given NBP table
+//
+// GetToday returns ErrNoExchangeRateForGivenDay if the table is not published yet, or is not published today at all.
+func (c *Client) GetToday(ctx context.Context, table Table, curr string) (*Rates, error) {
+	var rates Rates
+	if err := c.get(ctx, fmt.Sprintf("%s/%s/%s/today", apiBase, table, curr), &rates); err != nil {
+		return nil, err
+	}
+	return &rates, nil
+}
+
+// GetCurrent returns the most recently published currency exchange rate in a given NBP table
+func (c *Client) GetCurrent(ctx context.Context, table Table, curr string) (*Rates, error) {
+	var rates Rates
+	if err := c.get(ctx, fmt.Sprintf("%s/%s/%s", apiBase, table, curr), &rates); err != nil {
+		return nil, err
+	}
+	return &rates, nil
+}
+
+// GetRange returns the currency exchange rates published between from and to (inclusive) in a given NBP table
+//
+// Periods longer than MaxRangeDays are split into multiple API calls and the results are merged in order. Days
+// without publication are absent from the result. If there are no rates in the whole period GetRange returns
+// Rates with an empty Rates slice.
+func (c *Client) GetRange(ctx context.Context, table Table, curr string, from, to time.Time) (*Rates, error) {
+	result := &Rates{Table: string(table), Code: curr, Rates: []DailyRate{}}
+	for start := from; !start.After(to); start = start.AddDate(0, 0, MaxRangeDays) {
+		end := start.AddDate(0, 0, MaxRangeDays-1)
+		if end.After(to) {
+			end = to
+		}
+		var rates Rates
+		err := c.get(ctx, fmt.Sprintf("%s/%s/%s/%s/%s", apiBase, table, curr, start.Format("2006-01-02"), end.Format("2006-01-02")), &rates)
+		if err == ErrNoExchangeRateForGivenDay {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Currency = rates.Currency
+		result.Rates = append(result.Rates, rates.Rates...)
+	}
+	return result, nil
+}
+
+func (c *Client) get(ctx context.Context, url string, v any) error {
+	for attempt := 1; ; attempt++ {
+		err := c.fetch(ctx, url, v)
+		if err == nil || attempt >= c.retry.MaxAttempts || ctx.Err() != nil || !retryable(err) {
+			return err
+		}
+		if err := c.sleep(ctx, c.retry.delay(attempt, err)); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) fetch(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("can't create request: %w", err)
+	}
+	release, err := c.acquire(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("can't connect to NBP api: %w", err)
+		return err
 	}
+	defer release()
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return errTransport{fmt.Errorf("can't connect to NBP api: %w", err)}
+	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode == 404 {
 		buf, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return nil, fmt.Errorf("can't read response: %w", err)
+			return fmt.Errorf("can't read response: %w", err)
 		}
 		if bytes.Contains(buf, []byte("Brak danych")) {
-			return nil, ErrNoExchangeRateForGivenDay
+			return ErrNoExchangeRateForGivenDay
 		}
-		return nil, ErrNoRatesForCurrency
+		return ErrNoRatesForCurrency
 	}
 
 	if resp.StatusCode != 200 {
 		buf, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return nil, fmt.Errorf("can't read response: %w", err)
+			return fmt.Errorf("can't read response: %w", err)
+		}
+		return ErrApiCallUnsuccessful{
+			Code:       resp.StatusCode,
+			Body:       string(buf),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
 		}
-		return nil, ErrApiCallUnsuccessful{Code: resp.StatusCode, Body: string(buf)}
 	}
 
-	var rates Rates
-	if err := json.NewDecoder(resp.Body).Decode(&rates); err != nil {
-		return nil, fmt.Errorf("can't decode response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("can't decode response: %w", err)
 	}
-	return &rates, nil
+	return nil
 }
@@ -1,6 +1,8 @@
 package nbpapi
 
 import (
+	"context"
+	"errors"
 	"github.com/google/go-cmp/cmp"
 	"github.com/shopspring/decimal"
 	"io"
@@ -24,7 +26,11 @@ type mockClient struct {
 	urls map[string]mockResponse
 }
 
-func (m *mockClient) Get(url string) (*http.Response, error) {
+func (m *mockClient) Do(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	url := req.URL.String()
 	var resp mockResponse
 	var ok bool
 	if resp, ok = m.urls[url]; !ok {
@@ -43,6 +49,7 @@ func TestClient_Get(t *testing.T) {
 	tests := []struct {
 		name    string
 		urls    map[string]mockResponse
+		table   Table
 		curr    string
 		day     time.Time
 		want    *Rates
@@ -56,8 +63,9 @@ func TestClient_Get(t *testing.T) {
 					body: `{"table":"A","currency":"euro","code":"EUR","rates":[{"no":"074/A/NBP/2022","effectiveDate":"2022-04-15","mid":4.6378}]}`,
 				},
 			},
-			curr: "EUR",
-			day:  day(2022, 4, 15),
+			table: TableA,
+			curr:  "EUR",
+			day:   day(2022, 4, 15),
 			want: &Rates{
 				Table:    "A",
 				Currency: "euro",
@@ -80,8 +88,9 @@ func TestClient_Get(t *testing.T) {
 					body: `{"table":"A","currency":"frank szwajcarski","code":"CHF","rates":[{"no":"072/A/NBP/2021","effectiveDate":"2021-04-15","mid":4.1198}]}`,
 				},
 			},
-			curr: "CHF",
-			day:  day(2021, 4, 15),
+			table: TableA,
+			curr:  "CHF",
+			day:   day(2021, 4, 15),
 			want: &Rates{
 				Table:    "A",
 				Currency: "frank szwajcarski",
@@ -104,6 +113,7 @@ func TestClient_Get(t *testing.T) {
 					body: `404 NotFound - Not Found - Brak danych`,
 				},
 			},
+			table:   TableA,
 			curr:    "EUR",
 			day:     day(2022, 4, 16),
 			wantErr: true,
@@ -116,16 +126,69 @@ func TestClient_Get(t *testing.T) {
 					body: `404 NotFound`,
 				},
 			},
+			table:   TableA,
 			curr:    "DOGE",
 			day:     day(2022, 4, 15),
 			wantErr: true,
 		},
+		{
+			name: "Positive case table B",
+			urls: map[string]mockResponse{
+				"https://api.nbp.pl/api/exchangerates/rates/B/AFN/2022-04-13": {
+					code: 200,
+					body: `{"table":"B","currency":"afgani (Afganistan)","code":"AFN","rates":[{"no":"015/B/NBP/2022","effectiveDate":"2022-04-13","mid":0.048802}]}`,
+				},
+			},
+			table: TableB,
+			curr:  "AFN",
+			day:   day(2022, 4, 13),
+			want: &Rates{
+				Table:    "B",
+				Currency: "afgani (Afganistan)",
+				Code:     "AFN",
+				Rates: []DailyRate{
+					{
+						No:            "015/B/NBP/2022",
+						EffectiveDate: "2022-04-13",
+						Mid:           decimal.NewFromFloat(0.048802),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Positive case table C",
+			urls: map[string]mockResponse{
+				"https://api.nbp.pl/api/exchangerates/rates/C/USD/2022-04-15": {
+					code: 200,
+					body: `{"table":"C","currency":"dolar amerykański","code":"USD","rates":[{"no":"074/C/NBP/2022","tradingDate":"2022-04-14","effectiveDate":"2022-04-15","bid":4.2455,"ask":4.3313}]}`,
+				},
+			},
+			table: TableC,
+			curr:  "USD",
+			day:   day(2022, 4, 15),
+			want: &Rates{
+				Table:    "C",
+				Currency: "dolar amerykański",
+				Code:     "USD",
+				Rates: []DailyRate{
+					{
+						No:            "074/C/NBP/2022",
+						TradingDate:   "2022-04-14",
+						EffectiveDate: "2022-04-15",
+						Bid:           decimal.NewFromFloat(4.2455),
+						Ask:           decimal.NewFromFloat(4.3313),
+					},
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			c := Init(&mockClient{urls: tt.urls})
-			got, err := c.Get(tt.curr, tt.day)
+			got, err := c.Get(context.Background(), tt.table, tt.curr, tt.day)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Rate() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -136,3 +199,196 @@ func TestClient_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    map[string]mockResponse
+		table   Table
+		curr    string
+		from    time.Time
+		to      time.Time
+		want    *Rates
+		wantErr bool
+	}{
+		{
+			name: "Single request",
+			urls: map[string]mockResponse{
+				"https://api.nbp.pl/api/exchangerates/rates/A/EUR/2022-04-14/2022-04-19": {
+					code: 200,
+					body: `{"table":"A","currency":"euro","code":"EUR","rates":[{"no":"073/A/NBP/2022","effectiveDate":"2022-04-14","mid":4.6215},{"no":"074/A/NBP/2022","effectiveDate":"2022-04-15","mid":4.6378},{"no":"075/A/NBP/2022","effectiveDate":"2022-04-19","mid":4.6448}]}`,
+				},
+			},
+			table: TableA,
+			curr:  "EUR",
+			from:  day(2022, 4, 14),
+			to:    day(2022, 4, 19),
+			want: &Rates{
+				Table:    "A",
+				Currency: "euro",
+				Code:     "EUR",
+				Rates: []DailyRate{
+					{No: "073/A/NBP/2022", EffectiveDate: "2022-04-14", Mid: decimal.NewFromFloat(4.6215)},
+					{No: "074/A/NBP/2022", EffectiveDate: "2022-04-15", Mid: decimal.NewFromFloat(4.6378)},
+					{No: "075/A/NBP/2022", EffectiveDate: "2022-04-19", Mid: decimal.NewFromFloat(4.6448)},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Split at 93 days",
+			urls: map[string]mockResponse{
+				"https://api.nbp.pl/api/exchangerates/rates/A/EUR/2022-01-01/2022-04-03": {
+					code: 200,
+					body: `{"table":"A","currency":"euro","code":"EUR","rates":[{"no":"001/A/NBP/2022","effectiveDate":"2022-01-03","mid":4.5889},{"no":"064/A/NBP/2022","effectiveDate":"2022-04-01","mid":4.6505}]}`,
+				},
+				"https://api.nbp.pl/api/exchangerates/rates/A/EUR/2022-04-04/2022-04-05": {
+					code: 200,
+					body: `{"table":"A","currency":"euro","code":"EUR","rates":[{"no":"065/A/NBP/2022","effectiveDate":"2022-04-04","mid":4.6318},{"no":"066/A/NBP/2022","effectiveDate":"2022-04-05","mid":4.6045}]}`,
+				},
+			},
+			table: TableA,
+			curr:  "EUR",
+			from:  day(2022, 1, 1),
+			to:    day(2022, 4, 5),
+			want: &Rates{
+				Table:    "A",
+				Currency: "euro",
+				Code:     "EUR",
+				Rates: []DailyRate{
+					{No: "001/A/NBP/2022", EffectiveDate: "2022-01-03", Mid: decimal.NewFromFloat(4.5889)},
+					{No: "064/A/NBP/2022", EffectiveDate: "2022-04-01", Mid: decimal.NewFromFloat(4.6505)},
+					{No: "065/A/NBP/2022", EffectiveDate: "2022-04-04", Mid: decimal.NewFromFloat(4.6318)},
+					{No: "066/A/NBP/2022", EffectiveDate: "2022-04-05", Mid: decimal.NewFromFloat(4.6045)},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "No rates in the period",
+			urls: map[string]mockResponse{
+				"https://api.nbp.pl/api/exchangerates/rates/A/EUR/2022-04-16/2022-04-18": {
+					code: 404,
+					body: `404 NotFound - Not Found - Brak danych`,
+				},
+			},
+			table: TableA,
+			curr:  "EUR",
+			from:  day(2022, 4, 16),
+			to:    day(2022, 4, 18),
+			want: &Rates{
+				Table: "A",
+				Code:  "EUR",
+				Rates: []DailyRate{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Non existing currency",
+			urls: map[string]mockResponse{
+				"https://api.nbp.pl/api/exchangerates/rates/A/DOGE/2022-04-14/2022-04-19": {
+					code: 404,
+					body: `404 NotFound`,
+				},
+			},
+			table:   TableA,
+			curr:    "DOGE",
+			from:    day(2022, 4, 14),
+			to:      day(2022, 4, 19),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := Init(&mockClient{urls: tt.urls})
+			got, err := c.GetRange(context.Background(), tt.table, tt.curr, tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetRange() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_GetCancelled(t *testing.T) {
+	c := Init(&mockClient{urls: map[string]mockResponse{
+		"https://api.nbp.pl/api/exchangerates/rates/A/EUR/2022-04-15": {
+			code: 200,
+			body: `{"table":"A","currency":"euro","code":"EUR","rates":[{"no":"074/A/NBP/2022","effectiveDate":"2022-04-15","mid":4.6378}]}`,
+		},
+	}})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.Get(ctx, TableA, "EUR", day(2022, 4, 15))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Get() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestClient_GetLatest(t *testing.T) {
+	eur := func(rates ...DailyRate) *Rates {
+		return &Rates{Table: "A", Currency: "euro", Code: "EUR", Rates: rates}
+	}
+	r14 := DailyRate{No: "073/A/NBP/2022", EffectiveDate: "2022-04-14", Mid: decimal.NewFromFloat(4.6215)}
+	r15 := DailyRate{No: "074/A/NBP/2022", EffectiveDate: "2022-04-15", Mid: decimal.NewFromFloat(4.6378)}
+	urls := map[string]mockResponse{
+		"https://api.nbp.pl/api/exchangerates/rates/A/EUR/last/2": {
+			code: 200,
+			body: `{"table":"A","currency":"euro","code":"EUR","rates":[{"no":"073/A/NBP/2022","effectiveDate":"2022-04-14","mid":4.6215},{"no":"074/A/NBP/2022","effectiveDate":"2022-04-15","mid":4.6378}]}`,
+		},
+		"https://api.nbp.pl/api/exchangerates/rates/A/EUR/today": {
+			code: 404,
+			body: `404 NotFound - Not Found - Brak danych`,
+		},
+		"https://api.nbp.pl/api/exchangerates/rates/A/EUR": {
+			code: 200,
+			body: `{"table":"A","currency":"euro","code":"EUR","rates":[{"no":"074/A/NBP/2022","effectiveDate":"2022-04-15","mid":4.6378}]}`,
+		},
+	}
+	tests := []struct {
+		name    string
+		call    func(c *Client) (*Rates, error)
+		want    *Rates
+		wantErr error
+	}{
+		{
+			name: "Last rates",
+			call: func(c *Client) (*Rates, error) {
+				return c.GetLast(context.Background(), TableA, "EUR", 2)
+			},
+			want: eur(r14, r15),
+		},
+		{
+			name: "Not published today",
+			call: func(c *Client) (*Rates, error) {
+				return c.GetToday(context.Background(), TableA, "EUR")
+			},
+			wantErr: ErrNoExchangeRateForGivenDay,
+		},
+		{
+			name: "Current rate",
+			call: func(c *Client) (*Rates, error) {
+				return c.GetCurrent(context.Background(), TableA, "EUR")
+			},
+			want: eur(r15),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.call(Init(&mockClient{urls: urls}))
+			if err != tt.wantErr {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package nbpapi
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how failed API calls are retried
+//
+// Transport errors, 5xx and 429 Too Many Requests responses are retried with jittered exponential backoff. 404
+// responses are never retried, they mean there is no data.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one; 1 disables retries
+	MaxAttempts int
+	// BaseDelay is the backoff before the second attempt, it doubles with every following attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff, including the delay requested with the Retry-After header; 0 or less means no cap
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy retries a failed call twice, waiting up to 0.5s and 1s
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// WithRetryPolicy sets the retry policy of *Client instance
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// noMaxDelay is the cap of the backoff if RetryPolicy.MaxDelay is not set, it keeps the random delay from overflowing
+const noMaxDelay = time.Duration(math.MaxInt64 - 1)
+
+// delay returns a random backoff after a given failed attempt, but not shorter than the server requested
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = noMaxDelay
+	}
+	backoff := p.BaseDelay << (attempt - 1)
+	if backoff>>(attempt-1) != p.BaseDelay || backoff > maxDelay {
+		// the doubling overflowed or exceeded the cap
+		backoff = maxDelay
+	}
+	if backoff < 0 {
+		backoff = 0
+	}
+	d := time.Duration(rand.Int63n(int64(backoff) + 1))
+
+	var apiErr ErrApiCallUnsuccessful
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > d {
+		d = apiErr.RetryAfter
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+	return d
+}
+
+// errTransport marks the errors which happened before receiving the response
+type errTransport struct {
+	err error
+}
+
+func (e errTransport) Error() string {
+	return e.err.Error()
+}
+
+func (e errTransport) Unwrap() error {
+	return e.err
+}
+
+func retryable(err error) bool {
+	if errors.As(err, &errTransport{}) {
+		return true
+	}
+	var apiErr ErrApiCallUnsuccessful
+	if errors.As(err, &apiErr) {
+		return apiErr.Code >= 500 || apiErr.Code == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// parseRetryAfter parses the Retry-After header value, either delay in seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,265 @@
+package nbpapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+type sequenceResponse struct {
+	code       int
+	body       string
+	retryAfter string
+	err        error
+}
+
+// sequenceClient returns the responses in order, one per call
+type sequenceClient struct {
+	responses []sequenceResponse
+	calls     int
+}
+
+func (s *sequenceClient) Do(req *http.Request) (*http.Response, error) {
+	if s.calls >= len(s.responses) {
+		panic("unexpected call to " + req.URL.String())
+	}
+	resp := s.responses[s.calls]
+	s.calls++
+	if resp.err != nil {
+		return nil, resp.err
+	}
+	header := http.Header{}
+	if resp.retryAfter != "" {
+		header.Set("Retry-After", resp.retryAfter)
+	}
+	return &http.Response{
+		StatusCode: resp.code,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(resp.body)),
+	}, nil
+}
+
+const eurBody = `{"table":"A","currency":"euro","code":"EUR","rates":[{"no":"074/A/NBP/2022","effectiveDate":"2022-04-15","mid":4.6378}]}`
+
+func TestClient_GetRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 5 * time.Second}
+	tests := []struct {
+		name      string
+		responses []sequenceResponse
+		wantCalls int
+		wantErr   error
+		// wantSleep lists the expected delays, zero means any delay within the backoff
+		wantSleep []time.Duration
+	}{
+		{
+			name: "Success on the first attempt",
+			responses: []sequenceResponse{
+				{code: 200, body: eurBody},
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "Retry service unavailable",
+			responses: []sequenceResponse{
+				{code: 503, body: "Service Unavailable"},
+				{code: 200, body: eurBody},
+			},
+			wantCalls: 2,
+			wantSleep: []time.Duration{0},
+		},
+		{
+			name: "Retry transport error",
+			responses: []sequenceResponse{
+				{err: errors.New("connection reset by peer")},
+				{code: 200, body: eurBody},
+			},
+			wantCalls: 2,
+			wantSleep: []time.Duration{0},
+		},
+		{
+			name: "Respect Retry-After",
+			responses: []sequenceResponse{
+				{code: 429, body: "Too Many Requests", retryAfter: "2"},
+				{code: 200, body: eurBody},
+			},
+			wantCalls: 2,
+			wantSleep: []time.Duration{2 * time.Second},
+		},
+		{
+			name: "Cap Retry-After",
+			responses: []sequenceResponse{
+				{code: 503, body: "Service Unavailable", retryAfter: "120"},
+				{code: 200, body: eurBody},
+			},
+			wantCalls: 2,
+			wantSleep: []time.Duration{5 * time.Second},
+		},
+		{
+			name: "Give up after max attempts",
+			responses: []sequenceResponse{
+				{code: 500, body: "Internal Server Error"},
+				{code: 500, body: "Internal Server Error"},
+				{code: 500, body: "Internal Server Error"},
+			},
+			wantCalls: 3,
+			wantErr:   ErrApiCallUnsuccessful{Code: 500, Body: "Internal Server Error"},
+			wantSleep: []time.Duration{0, 0},
+		},
+		{
+			name: "Don't retry no data",
+			responses: []sequenceResponse{
+				{code: 404, body: "404 NotFound - Not Found - Brak danych"},
+			},
+			wantCalls: 1,
+			wantErr:   ErrNoExchangeRateForGivenDay,
+		},
+		{
+			name: "Don't retry unknown currency",
+			responses: []sequenceResponse{
+				{code: 404, body: "404 NotFound"},
+			},
+			wantCalls: 1,
+			wantErr:   ErrNoRatesForCurrency,
+		},
+		{
+			name: "Don't retry bad request",
+			responses: []sequenceResponse{
+				{code: 400, body: "400 BadRequest - Błędny zakres dat"},
+			},
+			wantCalls: 1,
+			wantErr:   ErrApiCallUnsuccessful{Code: 400, Body: "400 BadRequest - Błędny zakres dat"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			http := &sequenceClient{responses: tt.responses}
+			c := Init(http, WithRetryPolicy(policy))
+			var slept []time.Duration
+			c.sleep = func(ctx context.Context, d time.Duration) error {
+				slept = append(slept, d)
+				return nil
+			}
+
+			_, err := c.Get(context.Background(), TableA, "EUR", day(2022, 4, 15))
+			if diff := cmp.Diff(tt.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Get() error mismatch (-want +got):\n%s", diff)
+			}
+			if http.calls != tt.wantCalls {
+				t.Errorf("Get() made %d calls, want %d", http.calls, tt.wantCalls)
+			}
+			if len(slept) != len(tt.wantSleep) {
+				t.Errorf("Get() slept %v, want %v", slept, tt.wantSleep)
+				return
+			}
+			for i, want := range tt.wantSleep {
+				maxBackoff := policy.BaseDelay << i
+				if want == 0 && (slept[i] < 0 || slept[i] > maxBackoff) {
+					t.Errorf("Sleep %d = %v, want within [0, %v]", i, slept[i], maxBackoff)
+				}
+				if want != 0 && slept[i] != want {
+					t.Errorf("Sleep %d = %v, want %v", i, slept[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_GetRetryCancelled(t *testing.T) {
+	http := &sequenceClient{responses: []sequenceResponse{
+		{code: 503, body: "Service Unavailable", retryAfter: "1"},
+	}}
+	c := Init(http)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := c.Get(ctx, TableA, "EUR", day(2022, 4, 15))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if http.calls != 1 {
+		t.Errorf("Get() made %d calls, want 1", http.calls)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	retryAfter := ErrApiCallUnsuccessful{Code: 429, RetryAfter: time.Minute}
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		attempt  int
+		err      error
+		min, max time.Duration
+	}{
+		{
+			name:    "Backoff doubles with the attempt",
+			policy:  RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Hour},
+			attempt: 3,
+			max:     4 * time.Second,
+		},
+		{
+			name:    "Backoff is capped",
+			policy:  RetryPolicy{BaseDelay: time.Second, MaxDelay: 2 * time.Second},
+			attempt: 10,
+			max:     2 * time.Second,
+		},
+		{
+			name:    "Retry-After is capped",
+			policy:  RetryPolicy{BaseDelay: time.Second, MaxDelay: 2 * time.Second},
+			attempt: 1,
+			err:     retryAfter,
+			min:     2 * time.Second,
+			max:     2 * time.Second,
+		},
+		{
+			name:    "No cap without MaxDelay",
+			policy:  RetryPolicy{BaseDelay: time.Second},
+			attempt: 3,
+			err:     retryAfter,
+			min:     time.Minute,
+			max:     time.Minute,
+		},
+		{
+			name:    "Backoff without MaxDelay does not overflow",
+			policy:  RetryPolicy{BaseDelay: time.Second, MaxDelay: -1},
+			attempt: 100,
+			max:     noMaxDelay,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				if got := tt.policy.delay(tt.attempt, tt.err); got < tt.min || got > tt.max {
+					t.Fatalf("delay() = %v, want between %v and %v", got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2022, 4, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 0},
+		{value: "5", want: 5 * time.Second},
+		{value: "Fri, 15 Apr 2022 12:00:30 GMT", want: 30 * time.Second},
+		{value: "Fri, 15 Apr 2022 11:00:00 GMT", want: 0},
+		{value: "soon", want: 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
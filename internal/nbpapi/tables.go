@@ -0,0 +1,54 @@
+package nbpapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	tablesBase = "https://api.nbp.pl/api/exchangerates/tables"
+)
+
+// ExchangeTable represents a whole exchange rate table as returned by the NBP tables API
+type ExchangeTable struct {
+	Table         string      `json:"table"`
+	No            string      `json:"no"`
+	TradingDate   string      `json:"tradingDate,omitempty"`
+	EffectiveDate string      `json:"effectiveDate"`
+	Rates         []TableRate `json:"rates"`
+}
+
+// TableRate represents a single currency rate in an exchange rate table
+//
+// Tables A and B publish the Mid rate, table C publishes the Bid and Ask rates.
+type TableRate struct {
+	Currency string          `json:"currency"`
+	Code     string          `json:"code"`
+	Mid      decimal.Decimal `json:"mid"`
+	Bid      decimal.Decimal `json:"bid"`
+	Ask      decimal.Decimal `json:"ask"`
+}
+
+// GetTable returns a given NBP exchange rate table published for a given date
+func (c *Client) GetTable(ctx context.Context, table Table, day time.Time) (*ExchangeTable, error) {
+	return c.getTable(ctx, fmt.Sprintf("%s/%s/%s", tablesBase, table, day.Format("2006-01-02")))
+}
+
+// GetCurrentTable returns the most recently published NBP exchange rate table
+func (c *Client) GetCurrentTable(ctx context.Context, table Table) (*ExchangeTable, error) {
+	return c.getTable(ctx, fmt.Sprintf("%s/%s", tablesBase, table))
+}
+
+func (c *Client) getTable(ctx context.Context, url string) (*ExchangeTable, error) {
+	var tables []ExchangeTable
+	if err := c.get(ctx, url, &tables); err != nil {
+		return nil, err
+	}
+	if len(tables) != 1 {
+		return nil, fmt.Errorf("expectation failed: wanted a single table, instead got %d", len(tables))
+	}
+	return &tables[0], nil
+}
@@ -0,0 +1,115 @@
+package nbpapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+func TestClient_GetTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    map[string]mockResponse
+		table   Table
+		day     time.Time
+		want    *ExchangeTable
+		wantErr bool
+	}{
+		{
+			name: "Table A",
+			urls: map[string]mockResponse{
+				"https://api.nbp.pl/api/exchangerates/tables/A/2022-04-15": {
+					code: 200,
+					body: `[{"table":"A","no":"074/A/NBP/2022","effectiveDate":"2022-04-15","rates":[{"currency":"dolar amerykański","code":"USD","mid":4.2865},{"currency":"euro","code":"EUR","mid":4.6378}]}]`,
+				},
+			},
+			table: TableA,
+			day:   day(2022, 4, 15),
+			want: &ExchangeTable{
+				Table:         "A",
+				No:            "074/A/NBP/2022",
+				EffectiveDate: "2022-04-15",
+				Rates: []TableRate{
+					{Currency: "dolar amerykański", Code: "USD", Mid: decimal.NewFromFloat(4.2865)},
+					{Currency: "euro", Code: "EUR", Mid: decimal.NewFromFloat(4.6378)},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Table C",
+			urls: map[string]mockResponse{
+				"https://api.nbp.pl/api/exchangerates/tables/C/2022-04-15": {
+					code: 200,
+					body: `[{"table":"C","no":"074/C/NBP/2022","tradingDate":"2022-04-14","effectiveDate":"2022-04-15","rates":[{"currency":"dolar amerykański","code":"USD","bid":4.2455,"ask":4.3313}]}]`,
+				},
+			},
+			table: TableC,
+			day:   day(2022, 4, 15),
+			want: &ExchangeTable{
+				Table:         "C",
+				No:            "074/C/NBP/2022",
+				TradingDate:   "2022-04-14",
+				EffectiveDate: "2022-04-15",
+				Rates: []TableRate{
+					{Currency: "dolar amerykański", Code: "USD", Bid: decimal.NewFromFloat(4.2455), Ask: decimal.NewFromFloat(4.3313)},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Not found for a given day",
+			urls: map[string]mockResponse{
+				"https://api.nbp.pl/api/exchangerates/tables/A/2022-04-16": {
+					code: 404,
+					body: `404 NotFound - Not Found - Brak danych`,
+				},
+			},
+			table:   TableA,
+			day:     day(2022, 4, 16),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := Init(&mockClient{urls: tt.urls})
+			got, err := c.GetTable(context.Background(), tt.table, tt.day)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetTable() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetTable() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_GetCurrentTable(t *testing.T) {
+	c := Init(&mockClient{urls: map[string]mockResponse{
+		"https://api.nbp.pl/api/exchangerates/tables/B": {
+			code: 200,
+			body: `[{"table":"B","no":"015/B/NBP/2022","effectiveDate":"2022-04-13","rates":[{"currency":"afgani (Afganistan)","code":"AFN","mid":0.048802}]}]`,
+		},
+	}})
+	want := &ExchangeTable{
+		Table:         "B",
+		No:            "015/B/NBP/2022",
+		EffectiveDate: "2022-04-13",
+		Rates: []TableRate{
+			{Currency: "afgani (Afganistan)", Code: "AFN", Mid: decimal.NewFromFloat(0.048802)},
+		},
+	}
+	got, err := c.GetCurrentTable(context.Background(), TableB)
+	if err != nil {
+		t.Errorf("GetCurrentTable() error = %v, want no error", err)
+		return
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetCurrentTable() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,50 @@
+// Package publication describes when NBP publishes the exchange rate tables
+package publication
+
+import (
+	"time"
+	_ "time/tzdata"
+
+	"github.com/igor-kupczynski/gonbp/internal/nbpapi"
+)
+
+// Warsaw is the time zone NBP publication schedule is defined in
+var Warsaw = mustLoadLocation("Europe/Warsaw")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// Window returns the time range in which NBP publishes a given table for a given day
+//
+// Table C is published between 7:45 and 8:15, tables A and B between 11:45 and 12:15 Warsaw time. Only the civil
+// date of day is taken into account.
+func Window(table nbpapi.Table, day time.Time) (start, end time.Time) {
+	hour, min := 11, 45
+	if table == nbpapi.TableC {
+		hour, min = 7, 45
+	}
+	start = time.Date(day.Year(), day.Month(), day.Day(), hour, min, 0, 0, Warsaw)
+	return start, start.Add(30 * time.Minute)
+}
+
+// Deadline returns the time by which NBP publishes a given table for a given day, the end of its Window
+func Deadline(table nbpapi.Table, day time.Time) time.Time {
+	_, end := Window(table, day)
+	return end
+}
+
+// Day returns the civil date in Warsaw at a given time as midnight UTC
+func Day(t time.Time) time.Time {
+	year, month, day := t.In(Warsaw).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Today returns the current civil date in Warsaw as midnight UTC
+func Today(now time.Time) time.Time {
+	return Day(now)
+}
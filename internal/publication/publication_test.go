@@ -0,0 +1,107 @@
+package publication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/igor-kupczynski/gonbp/internal/nbpapi"
+)
+
+func TestDeadline(t *testing.T) {
+	tests := []struct {
+		name  string
+		table nbpapi.Table
+		day   time.Time
+		want  time.Time
+	}{
+		{
+			name:  "Table A in winter",
+			table: nbpapi.TableA,
+			day:   time.Date(2022, 1, 14, 0, 0, 0, 0, time.UTC),
+			want:  time.Date(2022, 1, 14, 11, 15, 0, 0, time.UTC),
+		},
+		{
+			name:  "Table A in summer",
+			table: nbpapi.TableA,
+			day:   time.Date(2022, 7, 14, 0, 0, 0, 0, time.UTC),
+			want:  time.Date(2022, 7, 14, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name:  "Table C",
+			table: nbpapi.TableC,
+			day:   time.Date(2022, 1, 14, 0, 0, 0, 0, time.UTC),
+			want:  time.Date(2022, 1, 14, 7, 15, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got := Deadline(tt.table, tt.day)
+			if !got.Equal(tt.want) {
+				t.Errorf("Deadline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		name      string
+		table     nbpapi.Table
+		day       time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "Table A in winter",
+			table:     nbpapi.TableA,
+			day:       time.Date(2022, 1, 14, 0, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2022, 1, 14, 10, 45, 0, 0, time.UTC),
+			wantEnd:   time.Date(2022, 1, 14, 11, 15, 0, 0, time.UTC),
+		},
+		{
+			name:      "Table C in summer",
+			table:     nbpapi.TableC,
+			day:       time.Date(2022, 7, 14, 0, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2022, 7, 14, 5, 45, 0, 0, time.UTC),
+			wantEnd:   time.Date(2022, 7, 14, 6, 15, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := Window(tt.table, tt.day)
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("Window() = %v, %v, want %v, %v", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestToday(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "Same day",
+			now:  time.Date(2022, 4, 15, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2022, 4, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Evening in New York is the next day in Warsaw",
+			now:  time.Date(2022, 4, 15, 20, 0, 0, 0, mustLoadLocation("America/New_York")),
+			want: time.Date(2022, 4, 16, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got := Today(tt.now)
+			if !got.Equal(tt.want) {
+				t.Errorf("Today() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package gonbp
+
+import (
+	"time"
+
+	"github.com/igor-kupczynski/gonbp/cache"
+	"github.com/igor-kupczynski/gonbp/internal/nbpapi"
+)
+
+const (
+	// DefaultMaxLookback is the default number of days PreviousRate and NextRate check before giving up
+	DefaultMaxLookback = 14
+
+	// DefaultRateLimit is the default average number of NBP API calls per second
+	DefaultRateLimit = 10
+	// DefaultRateLimitBurst is the default number of NBP API calls allowed in a burst above DefaultRateLimit
+	DefaultRateLimitBurst = 10
+	// DefaultMaxInFlight is the default maximum number of concurrent NBP API calls
+	DefaultMaxInFlight = 4
+)
+
+// Cache is a key-value store for the NBP API responses, see package cache for the available backends
+type Cache = cache.Cache
+
+// RetryPolicy configures how failed NBP API calls are retried
+//
+// Transport errors, 5xx and 429 Too Many Requests responses are retried with jittered exponential backoff, honoring
+// the Retry-After header. Responses meaning there is no data, e.g. ErrNoExchangeRateForGivenDay, are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one; 1 disables retries
+	MaxAttempts int
+	// BaseDelay is the backoff before the second attempt, it doubles with every following attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff, including the delay requested with the Retry-After header; 0 or less means no cap
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy retries a failed call twice, waiting up to 0.5s and 1s
+var DefaultRetryPolicy = RetryPolicy(nbpapi.DefaultRetryPolicy)
+
+type options struct {
+	maxLookback int
+	cache       Cache
+	retry       RetryPolicy
+	rateLimit   float64
+	rateBurst   int
+	maxInFlight int
+}
+
+// Option configures *NBP instance
+type Option func(*options)
+
+// WithMaxLookback sets the number of days PreviousRate and NextRate check before giving up
+//
+// The default is DefaultMaxLookback. Values lower than 1 are ignored.
+func WithMaxLookback(days int) Option {
+	return func(o *options) {
+		if days > 0 {
+			o.maxLookback = days
+		}
+	}
+}
+
+// WithCache sets the cache for the NBP API responses
+//
+// The default is cache.File under the cacheDir passed to Init. Use cache.Memory to keep the responses in memory only,
+// or cache.Nop to disable caching.
+func WithCache(c Cache) Option {
+	return func(o *options) {
+		o.cache = c
+	}
+}
+
+// WithRetryPolicy sets the policy for retrying failed NBP API calls
+//
+// The default is DefaultRetryPolicy.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = p
+	}
+}
+
+// WithRateLimit limits the NBP API calls to perSecond on average, with bursts of up to burst calls
+//
+// The limit applies to all the calls made by *NBP instance, including retries; the cached responses are not limited.
+// The default is DefaultRateLimit with DefaultRateLimitBurst. A non-positive perSecond disables the limit.
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(o *options) {
+		o.rateLimit = perSecond
+		o.rateBurst = burst
+	}
+}
+
+// WithMaxInFlight limits the number of concurrent NBP API calls
+//
+// The default is DefaultMaxInFlight. A non-positive n disables the limit.
+func WithMaxInFlight(n int) Option {
+	return func(o *options) {
+		o.maxInFlight = n
+	}
+}
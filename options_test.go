@@ -0,0 +1,136 @@
+package gonbp
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/igor-kupczynski/gonbp/cache"
+	"github.com/shopspring/decimal"
+)
+
+func TestWithMaxLookback(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want int
+	}{
+		{
+			name: "Default",
+			want: DefaultMaxLookback,
+		},
+		{
+			name: "Custom lookback",
+			opts: []Option{WithMaxLookback(30)},
+			want: 30,
+		},
+		{
+			name: "Invalid lookback is ignored",
+			opts: []Option{WithMaxLookback(0)},
+			want: DefaultMaxLookback,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			n := Init(t.TempDir(), http.DefaultClient, tt.opts...)
+			if got := n.MaxLookback(); got != tt.want {
+				t.Errorf("MaxLookback() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithCache(t *testing.T) {
+	c := cache.NewMemory(10)
+	err := c.Set(
+		"A/EUR/2022-04-15",
+		[]byte(`{"Rates":{"table":"A","currency":"euro","code":"EUR","rates":[{"no":"074/A/NBP/2022","effectiveDate":"2022-04-15","mid":4.6378}]}}`),
+	)
+	if err != nil {
+		t.Fatalf("Set() error = %v, want no error", err)
+	}
+	n := Init("", http.DefaultClient, WithCache(c))
+
+	want := &Rate{
+		TableNo: "074/A/NBP/2022",
+		Day:     date(2022, 4, 15),
+		Mid:     decimal.NewFromFloat(4.6378),
+	}
+	got, err := n.Rate(EUR, day(2022, 4, 15))
+	if err != nil {
+		t.Errorf("Rate() error = %v, want no error", err)
+		return
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Rate() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// retryAfterTransport fails the first request with 503 Service Unavailable and a Retry-After header
+type retryAfterTransport struct {
+	requests int
+}
+
+func (r *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.requests++
+	if r.requests == 1 {
+		return &http.Response{
+			StatusCode: 503,
+			Header:     http.Header{"Retry-After": []string{"1"}},
+			Body:       io.NopCloser(strings.NewReader("Service Unavailable")),
+		}, nil
+	}
+	return &http.Response{
+		StatusCode: 200,
+		Body: io.NopCloser(strings.NewReader(
+			`{"table":"A","currency":"euro","code":"EUR","rates":[{"no":"074/A/NBP/2022","effectiveDate":"2022-04-15","mid":4.6378}]}`,
+		)),
+	}, nil
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name             string
+		policy           RetryPolicy
+		minWait, maxWait time.Duration
+	}{
+		{
+			name:    "Retry-After is capped",
+			policy:  RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+			maxWait: 500 * time.Millisecond,
+		},
+		{
+			name:    "No cap without MaxDelay",
+			policy:  RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+			minWait: time.Second,
+			maxWait: time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &retryAfterTransport{}
+			n := Init(
+				"",
+				&http.Client{Transport: transport},
+				WithCache(cache.NewMemory(10)),
+				WithRateLimit(0, 0),
+				WithRetryPolicy(tt.policy),
+			)
+			start := time.Now()
+			if _, err := n.Rate(EUR, day(2022, 4, 15)); err != nil {
+				t.Fatalf("Rate() error = %v, want no error", err)
+			}
+			if waited := time.Since(start); waited < tt.minWait || waited > tt.maxWait {
+				t.Errorf("Rate() waited %v, want between %v and %v", waited, tt.minWait, tt.maxWait)
+			}
+			if transport.requests != 2 {
+				t.Errorf("Rate() made %d requests, want 2", transport.requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package gonbp
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/igor-kupczynski/gonbp/calendar"
+	"github.com/igor-kupczynski/gonbp/internal/nbpapi"
+	"github.com/igor-kupczynski/gonbp/internal/publication"
+)
+
+// Warsaw is the time zone of the NBP publication schedule
+//
+// Today, and the check whether a table for today is published yet, take the current date in Warsaw. A time.Time passed
+// as a day, e.g. to Rate, keeps the civil date of its own location, see DateOf.
+var Warsaw = publication.Warsaw
+
+// ErrNotYetPublished represents a failure where the table for today is expected, but NBP hasn't published it yet
+//
+// ErrNotYetPublished wraps ErrNoExchangeRateForGivenDay.
+type ErrNotYetPublished struct {
+	// Table is the table not published yet
+	Table Table
+	// Day is the day of the table
+	Day Date
+	// Deadline is the end of the publication window of the table
+	Deadline time.Time
+}
+
+func (e ErrNotYetPublished) Error() string {
+	return fmt.Sprintf(
+		"table %s for %s not published yet, expected by %s Warsaw time",
+		e.Table, e.Day, e.Deadline.In(Warsaw).Format("15:04"),
+	)
+}
+
+func (e ErrNotYetPublished) Unwrap() error {
+	return ErrNoExchangeRateForGivenDay
+}
+
+// PublicationWindow returns the time range in which NBP publishes a given table for a given day
+//
+// Tables A and B are published between 11:45 and 12:15, table C between 7:45 and 8:15 Warsaw time. The window is
+// returned for any day, use Publishes to check if the table is published on that day at all.
+func PublicationWindow(table Table, day Date) (start, end time.Time) {
+	return publication.Window(nbpapi.Table(table), day.Time())
+}
+
+// Publishes reports whether NBP publishes a given table on a given day
+//
+// Tables A and C are published on business days, see package calendar, and table B on business day Wednesdays.
+func Publishes(table Table, day Date) bool {
+	if !calendar.IsBusinessDay(day.Time()) {
+		return false
+	}
+	return table != TableB || day.Weekday() == time.Wednesday
+}
+
+// notPublishedYet turns ErrNoExchangeRateForGivenDay for today into ErrNotYetPublished before the publication deadline
+//
+// Other errors, and the days on which the table is not published at all, are returned as is.
+func (n *NBP) notPublishedYet(table Table, day Date, err error) error {
+	if !errors.Is(err, ErrNoExchangeRateForGivenDay) {
+		return err
+	}
+	now := n.now()
+	if day != warsawToday(now) || !Publishes(table, day) {
+		return err
+	}
+	_, deadline := PublicationWindow(table, day)
+	if !now.Before(deadline) {
+		return err
+	}
+	return ErrNotYetPublished{Table: table, Day: day, Deadline: deadline}
+}
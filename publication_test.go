@@ -0,0 +1,120 @@
+package gonbp
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/igor-kupczynski/gonbp/internal/nbpapi"
+	"github.com/shopspring/decimal"
+)
+
+func TestPublicationWindow(t *testing.T) {
+	start, end := PublicationWindow(TableA, date(2022, 4, 19))
+	wantStart, wantEnd := time.Date(2022, 4, 19, 9, 45, 0, 0, time.UTC), time.Date(2022, 4, 19, 10, 15, 0, 0, time.UTC)
+	if !start.Equal(wantStart) || !end.Equal(wantEnd) {
+		t.Errorf("PublicationWindow() = %v, %v, want %v, %v", start, end, wantStart, wantEnd)
+	}
+}
+
+func TestPublishes(t *testing.T) {
+	tests := []struct {
+		name  string
+		table Table
+		day   Date
+		want  bool
+	}{
+		{name: "Table A on a business day", table: TableA, day: date(2022, 4, 19), want: true},
+		{name: "Table A on Easter Monday", table: TableA, day: date(2022, 4, 18), want: false},
+		{name: "Table B on Wednesday", table: TableB, day: date(2022, 4, 20), want: true},
+		{name: "Table B on Tuesday", table: TableB, day: date(2022, 4, 19), want: false},
+		{name: "Table C on Saturday", table: TableC, day: date(2022, 4, 16), want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Publishes(tt.table, tt.day); got != tt.want {
+				t.Errorf("Publishes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNBP_NotYetPublished(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		urls map[string]mockResponse
+		want *ErrNotYetPublished
+	}{
+		{
+			name: "Before the publication window",
+			now:  time.Date(2022, 4, 19, 11, 0, 0, 0, Warsaw),
+			urls: map[string]mockResponse{
+				"A/EUR/today": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+			},
+			want: &ErrNotYetPublished{
+				Table:    TableA,
+				Day:      date(2022, 4, 19),
+				Deadline: time.Date(2022, 4, 19, 12, 15, 0, 0, Warsaw),
+			},
+		},
+		{
+			name: "After the publication window",
+			now:  time.Date(2022, 4, 19, 13, 0, 0, 0, Warsaw),
+			urls: map[string]mockResponse{
+				"A/EUR/today": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+			},
+			want: nil,
+		},
+		{
+			name: "No table today",
+			now:  time.Date(2022, 4, 18, 11, 0, 0, 0, Warsaw),
+			urls: map[string]mockResponse{
+				"A/EUR/today": {err: nbpapi.ErrNoExchangeRateForGivenDay},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			n := testNBP(&mockClient{urls: tt.urls})
+			n.now = func() time.Time { return tt.now }
+			_, err := n.Today(EUR)
+			if !errors.Is(err, ErrNoExchangeRateForGivenDay) {
+				t.Fatalf("Today() error = %v, want it to wrap %v", err, ErrNoExchangeRateForGivenDay)
+			}
+			var got *ErrNotYetPublished
+			if notYet := (ErrNotYetPublished{}); errors.As(err, &notYet) {
+				got = &notYet
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Today() error mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNBP_RateCivilDate(t *testing.T) {
+	// 20:00 in New York is already the next day in Warsaw, but the rate is for the date in New York
+	n := testNBP(&mockClient{urls: map[string]mockResponse{
+		"A/EUR/2022-04-19": {rates: &nbpapi.Rates{Table: "A", Currency: "euro", Code: "EUR", Rates: []nbpapi.DailyRate{
+			{No: "075/A/NBP/2022", EffectiveDate: "2022-04-19", Mid: decimal.NewFromFloat(4.6448)},
+		}}},
+	}})
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := n.Rate(EUR, time.Date(2022, 4, 19, 20, 0, 0, 0, newYork))
+	if err != nil {
+		t.Fatalf("Rate() error = %v, want no error", err)
+	}
+	want := &Rate{TableNo: "075/A/NBP/2022", Day: date(2022, 4, 19), Mid: decimal.NewFromFloat(4.6448)}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Rate() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,133 @@
+package gains
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/igor-kupczynski/gonbp"
+	"github.com/shopspring/decimal"
+)
+
+// tradeColumns are the columns of the trades CSV, commission can be left empty
+var tradeColumns = []string{"date", "instrument", "side", "quantity", "price", "commission", "currency"}
+
+// ReadTrades reads the trades from a CSV with a header row
+//
+// The header names the date, instrument, side, quantity, price, commission and currency columns, in any order. The
+// dates are formatted as 2006-01-02, the sides are buy or sell, and the numbers use a dot as the decimal separator.
+func ReadTrades(r io.Reader) ([]Trade, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("can't read trades: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("can't read trades: missing header row")
+	}
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range tradeColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("can't read trades: missing %s column in the header row", name)
+		}
+	}
+
+	trades := make([]Trade, 0, len(records)-1)
+	for i, record := range records[1:] {
+		line := i + 2
+		field := func(name string) string {
+			return strings.TrimSpace(record[columns[name]])
+		}
+		date, err := gonbp.ParseDate(field("date"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: can't parse date: %w", line, err)
+		}
+		side := Side(strings.ToLower(field("side")))
+		if side != Buy && side != Sell {
+			return nil, fmt.Errorf("line %d: unknown side %q, want buy or sell", line, field("side"))
+		}
+		curr, err := gonbp.ParseCurrency(field("currency"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		numbers := make(map[string]decimal.Decimal)
+		for _, name := range []string{"quantity", "price", "commission"} {
+			if name == "commission" && field(name) == "" {
+				continue
+			}
+			n, err := decimal.NewFromString(field(name))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: can't parse %s: %w", line, name, err)
+			}
+			numbers[name] = n
+		}
+		trades = append(trades, Trade{
+			Date:       date,
+			Instrument: field("instrument"),
+			Side:       side,
+			Quantity:   numbers["quantity"],
+			Price:      numbers["price"],
+			Commission: numbers["commission"],
+			Currency:   curr,
+		})
+	}
+	return trades, nil
+}
+
+// WriteLots writes the lots as CSV with a header row
+func WriteLots(w io.Writer, lots []Lot) error {
+	out := csv.NewWriter(w)
+	header := []string{
+		"instrument", "quantity",
+		"buy_date", "buy_price", "buy_currency", "buy_rate", "buy_table_no", "buy_rate_date",
+		"sell_date", "sell_price", "sell_currency", "sell_rate", "sell_table_no", "sell_rate_date",
+		"cost_basis", "proceeds", "commissions", "gain",
+	}
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	for _, l := range lots {
+		record := []string{l.Instrument, l.Quantity.String()}
+		record = append(record, legFields(l.Buy)...)
+		record = append(record, legFields(l.Sell)...)
+		record = append(record,
+			l.CostBasis.StringFixed(2), l.Proceeds.StringFixed(2), l.Commissions.StringFixed(2), l.Gain.StringFixed(2),
+		)
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func legFields(l Leg) []string {
+	fields := []string{l.Date.String(), l.Price.String(), string(l.Currency), "", "", ""}
+	if l.Rate != nil {
+		fields[3], fields[4], fields[5] = l.Rate.Mid.String(), l.Rate.TableNo, l.Rate.Day.String()
+	}
+	return fields
+}
+
+// WriteYears writes the yearly summaries as CSV with a header row
+func WriteYears(w io.Writer, years []YearSummary) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"year", "proceeds", "cost_basis", "commissions", "gain"}); err != nil {
+		return err
+	}
+	for _, y := range years {
+		record := []string{
+			strconv.Itoa(y.Year),
+			y.Proceeds.StringFixed(2), y.CostBasis.StringFixed(2), y.Commissions.StringFixed(2), y.Gain.StringFixed(2),
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,107 @@
+package gains
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/igor-kupczynski/gonbp"
+)
+
+func TestReadTrades(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    []Trade
+		wantErr string
+	}{
+		{
+			name: "Columns in any order, optional commission",
+			csv: "Currency,Date,Side,Instrument,Quantity,Price,Commission\n" +
+				"usd,2021-03-10,BUY,ACME,10,100,5\n" +
+				"USD,2022-04-19,sell,ACME,10,150,\n",
+			want: []Trade{
+				{Date: day(2021, 3, 10), Instrument: "ACME", Side: Buy, Quantity: dec("10"), Price: dec("100"), Commission: dec("5"), Currency: gonbp.USD},
+				{Date: day(2022, 4, 19), Instrument: "ACME", Side: Sell, Quantity: dec("10"), Price: dec("150"), Currency: gonbp.USD},
+			},
+		},
+		{
+			name:    "Missing column",
+			csv:     "date,instrument,side,quantity,price,currency\n",
+			wantErr: "missing commission column",
+		},
+		{
+			name:    "Unknown side",
+			csv:     "date,instrument,side,quantity,price,commission,currency\n2021-03-10,ACME,short,10,100,5,USD\n",
+			wantErr: `line 2: unknown side "short"`,
+		},
+		{
+			name:    "Invalid quantity",
+			csv:     "date,instrument,side,quantity,price,commission,currency\n2021-03-10,ACME,buy,ten,100,5,USD\n",
+			wantErr: "line 2: can't parse quantity",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadTrades(strings.NewReader(tt.csv))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ReadTrades() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ReadTrades() error = %v, want no error", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ReadTrades() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	report := &Report{
+		Lots: []Lot{
+			{
+				Instrument:  "ACME",
+				Quantity:    dec("10"),
+				Buy:         Leg{Date: day(2021, 3, 10), Price: dec("100"), Currency: gonbp.USD, Rate: usd0310},
+				Sell:        Leg{Date: day(2022, 4, 19), Price: dec("150"), Currency: gonbp.PLN},
+				CostBasis:   dec("3800"),
+				Proceeds:    dec("1500"),
+				Commissions: dec("19"),
+				Gain:        dec("-2319"),
+			},
+		},
+		Years: []YearSummary{
+			{Year: 2022, Proceeds: dec("1500"), CostBasis: dec("3800"), Commissions: dec("19"), Gain: dec("-2319")},
+		},
+	}
+
+	var lots bytes.Buffer
+	if err := WriteLots(&lots, report.Lots); err != nil {
+		t.Errorf("WriteLots() error = %v, want no error", err)
+	}
+	wantLots := "instrument,quantity," +
+		"buy_date,buy_price,buy_currency,buy_rate,buy_table_no,buy_rate_date," +
+		"sell_date,sell_price,sell_currency,sell_rate,sell_table_no,sell_rate_date," +
+		"cost_basis,proceeds,commissions,gain\n" +
+		"ACME,10,2021-03-10,100,USD,3.8,046/A/NBP/2021,2021-03-09,2022-04-19,150,PLN,,,,3800.00,1500.00,19.00,-2319.00\n"
+	if diff := cmp.Diff(wantLots, lots.String()); diff != "" {
+		t.Errorf("WriteLots() mismatch (-want +got):\n%s", diff)
+	}
+
+	var years bytes.Buffer
+	if err := WriteYears(&years, report.Years); err != nil {
+		t.Errorf("WriteYears() error = %v, want no error", err)
+	}
+	wantYears := "year,proceeds,cost_basis,commissions,gain\n" +
+		"2022,1500.00,3800.00,19.00,-2319.00\n"
+	if diff := cmp.Diff(wantYears, years.String()); diff != "" {
+		t.Errorf("WriteYears() mismatch (-want +got):\n%s", diff)
+	}
+}
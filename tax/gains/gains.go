@@ -0,0 +1,214 @@
+// Package gains matches buy and sell trades FIFO per instrument and reports the capital gains in PLN
+//
+// Each leg of a trade is converted to PLN with the NBP mid rate from the last business day before the trade date, as
+// the Polish income tax rules require, see package tax.
+package gains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/igor-kupczynski/gonbp"
+	"github.com/shopspring/decimal"
+)
+
+type rateSource interface {
+	PreviousRateOnContext(ctx context.Context, curr gonbp.Currency, day gonbp.Date) (*gonbp.Rate, error)
+}
+
+// Calculator calculates the capital gains with the NBP mid rates
+type Calculator struct {
+	rates rateSource
+}
+
+// New returns *Calculator instance using a given *gonbp.NBP instance
+func New(nbp *gonbp.NBP) *Calculator {
+	return &Calculator{rates: nbp}
+}
+
+// Side is the side of a trade, Buy or Sell
+type Side string
+
+const (
+	Buy  Side = "buy"
+	Sell Side = "sell"
+)
+
+// Trade is a single buy or sell of an instrument
+type Trade struct {
+	Date       gonbp.Date
+	Instrument string
+	Side       Side
+	Quantity   decimal.Decimal
+	// Price is the price of a single unit in Currency
+	Price decimal.Decimal
+	// Commission is the commission for the whole trade in Currency
+	Commission decimal.Decimal
+	Currency   gonbp.Currency
+}
+
+// Leg is one side of a lot, with the rate used to convert it to PLN
+type Leg struct {
+	Date     gonbp.Date
+	Price    decimal.Decimal
+	Currency gonbp.Currency
+	// Rate is the NBP mid rate applied, nil for PLN trades
+	Rate *gonbp.Rate
+}
+
+// Lot is a quantity of an instrument bought in one trade and sold in another
+//
+// A trade matched against multiple trades on the other side is split into multiple lots. Its commission is split
+// between the lots in proportion to their quantities. All PLN amounts are rounded half-up to grosze.
+type Lot struct {
+	Instrument string
+	Quantity   decimal.Decimal
+	Buy        Leg
+	Sell       Leg
+	// CostBasis is the PLN value of the lot at the buy price
+	CostBasis decimal.Decimal
+	// Proceeds is the PLN value of the lot at the sell price
+	Proceeds decimal.Decimal
+	// Commissions is the PLN value of the buy and sell commissions attributed to the lot
+	Commissions decimal.Decimal
+	// Gain is Proceeds less CostBasis and Commissions, negative for a loss
+	Gain decimal.Decimal
+}
+
+// YearSummary sums up the lots sold in a given year
+type YearSummary struct {
+	Year        int
+	Proceeds    decimal.Decimal
+	CostBasis   decimal.Decimal
+	Commissions decimal.Decimal
+	Gain        decimal.Decimal
+}
+
+// Report is the result of matching the trades
+type Report struct {
+	// Lots are ordered by the sell date
+	Lots []Lot
+	// Years are ordered by the year
+	Years []YearSummary
+}
+
+// ErrShortSale represents a failure where a sell exceeds the quantity of an instrument bought before
+var ErrShortSale = errors.New("sell exceeds the open position")
+
+// Calculate matches the sells to the earlier buys of the same instrument, first in first out
+//
+// The trades are processed by date, the trades on the same date in the given order. Buys left unmatched are not
+// reported.
+func (c *Calculator) Calculate(trades []Trade) (*Report, error) {
+	return c.CalculateContext(context.Background(), trades)
+}
+
+// CalculateContext is like Calculate, but the NBP API calls are bound to ctx
+func (c *Calculator) CalculateContext(ctx context.Context, trades []Trade) (*Report, error) {
+	sorted := append([]Trade(nil), trades...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	// open holds the unmatched part of the buys per instrument, oldest first
+	type position struct {
+		trade     Trade
+		leg       Leg
+		remaining decimal.Decimal
+	}
+	open := make(map[string][]*position)
+	report := &Report{}
+	for _, trade := range sorted {
+		if !trade.Quantity.IsPositive() {
+			return nil, fmt.Errorf("%s %s on %s: quantity must be positive", trade.Side, trade.Instrument, trade.Date)
+		}
+		leg, err := c.leg(ctx, trade)
+		if err != nil {
+			return nil, err
+		}
+		switch trade.Side {
+		case Buy:
+			open[trade.Instrument] = append(open[trade.Instrument], &position{trade: trade, leg: leg, remaining: trade.Quantity})
+		case Sell:
+			remaining := trade.Quantity
+			for remaining.IsPositive() {
+				positions := open[trade.Instrument]
+				if len(positions) == 0 {
+					return nil, fmt.Errorf(
+						"%w: sell of %s %s on %s",
+						ErrShortSale, trade.Quantity, trade.Instrument, trade.Date,
+					)
+				}
+				buy := positions[0]
+				quantity := decimal.Min(remaining, buy.remaining)
+				report.Lots = append(report.Lots, lot(buy.trade, buy.leg, trade, leg, quantity))
+				remaining = remaining.Sub(quantity)
+				buy.remaining = buy.remaining.Sub(quantity)
+				if buy.remaining.IsZero() {
+					open[trade.Instrument] = positions[1:]
+				}
+			}
+		default:
+			return nil, fmt.Errorf("%s on %s: unknown side %q", trade.Instrument, trade.Date, trade.Side)
+		}
+	}
+	report.Years = summarize(report.Lots)
+	return report, nil
+}
+
+func (c *Calculator) leg(ctx context.Context, trade Trade) (Leg, error) {
+	leg := Leg{Date: trade.Date, Price: trade.Price, Currency: trade.Currency}
+	if trade.Currency == gonbp.PLN {
+		return leg, nil
+	}
+	rate, err := c.rates.PreviousRateOnContext(ctx, trade.Currency, trade.Date)
+	if err != nil {
+		return Leg{}, fmt.Errorf(
+			"%s %s on %s: can't fetch %s rate: %w",
+			trade.Side, trade.Instrument, trade.Date, trade.Currency, err,
+		)
+	}
+	leg.Rate = rate
+	return leg, nil
+}
+
+// pln converts an amount in the leg currency to PLN, rounded to grosze
+func (l Leg) pln(amount decimal.Decimal) decimal.Decimal {
+	if l.Rate != nil {
+		amount = amount.Mul(l.Rate.Mid)
+	}
+	return gonbp.RoundHalfUp.Round(amount, 2)
+}
+
+func lot(buy Trade, buyLeg Leg, sell Trade, sellLeg Leg, quantity decimal.Decimal) Lot {
+	l := Lot{
+		Instrument: buy.Instrument,
+		Quantity:   quantity,
+		Buy:        buyLeg,
+		Sell:       sellLeg,
+		CostBasis:  buyLeg.pln(quantity.Mul(buy.Price)),
+		Proceeds:   sellLeg.pln(quantity.Mul(sell.Price)),
+		Commissions: buyLeg.pln(buy.Commission.Mul(quantity).Div(buy.Quantity)).
+			Add(sellLeg.pln(sell.Commission.Mul(quantity).Div(sell.Quantity))),
+	}
+	l.Gain = l.Proceeds.Sub(l.CostBasis).Sub(l.Commissions)
+	return l
+}
+
+func summarize(lots []Lot) []YearSummary {
+	var years []YearSummary
+	for _, l := range lots {
+		year := l.Sell.Date.Year
+		if len(years) == 0 || years[len(years)-1].Year != year {
+			years = append(years, YearSummary{Year: year})
+		}
+		y := &years[len(years)-1]
+		y.Proceeds = y.Proceeds.Add(l.Proceeds)
+		y.CostBasis = y.CostBasis.Add(l.CostBasis)
+		y.Commissions = y.Commissions.Add(l.Commissions)
+		y.Gain = y.Gain.Add(l.Gain)
+	}
+	return years
+}
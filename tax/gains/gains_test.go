@@ -0,0 +1,126 @@
+package gains
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/igor-kupczynski/gonbp"
+	"github.com/shopspring/decimal"
+)
+
+func day(year, month, day int) gonbp.Date {
+	return gonbp.NewDate(year, time.Month(month), day)
+}
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+// mockRates returns the previous rates keyed by currency and the trade date
+type mockRates struct {
+	rates map[string]*gonbp.Rate
+}
+
+func (m *mockRates) PreviousRateOnContext(ctx context.Context, curr gonbp.Currency, day gonbp.Date) (*gonbp.Rate, error) {
+	key := fmt.Sprintf("%s/%s", curr, day)
+	rate, ok := m.rates[key]
+	if !ok {
+		panic("response not set up for " + key)
+	}
+	return rate, nil
+}
+
+var (
+	usd0310 = &gonbp.Rate{TableNo: "046/A/NBP/2021", Day: day(2021, 3, 9), Mid: dec("3.8")}
+	usd0615 = &gonbp.Rate{TableNo: "113/A/NBP/2021", Day: day(2021, 6, 14), Mid: dec("3.75")}
+	usd0419 = &gonbp.Rate{TableNo: "074/A/NBP/2022", Day: day(2022, 4, 15), Mid: dec("4.2865")}
+
+	testRates = &mockRates{rates: map[string]*gonbp.Rate{
+		"USD/2021-03-10": usd0310,
+		"USD/2021-06-15": usd0615,
+		"USD/2022-04-19": usd0419,
+	}}
+)
+
+func TestCalculator_Calculate(t *testing.T) {
+	tests := []struct {
+		name    string
+		trades  []Trade
+		want    *Report
+		wantErr error
+	}{
+		{
+			name: "FIFO over two buys, and a PLN loss",
+			trades: []Trade{
+				{Date: day(2022, 4, 19), Instrument: "ACME", Side: Sell, Quantity: dec("12"), Price: dec("150"), Commission: dec("6"), Currency: gonbp.USD},
+				{Date: day(2021, 3, 10), Instrument: "ACME", Side: Buy, Quantity: dec("10"), Price: dec("100"), Commission: dec("5"), Currency: gonbp.USD},
+				{Date: day(2021, 6, 15), Instrument: "ACME", Side: Buy, Quantity: dec("5"), Price: dec("120"), Commission: dec("2"), Currency: gonbp.USD},
+				{Date: day(2021, 1, 5), Instrument: "XYZ", Side: Buy, Quantity: dec("1"), Price: dec("50"), Commission: dec("1"), Currency: gonbp.PLN},
+				{Date: day(2021, 12, 1), Instrument: "XYZ", Side: Sell, Quantity: dec("1"), Price: dec("40"), Commission: dec("1"), Currency: gonbp.PLN},
+			},
+			want: &Report{
+				Lots: []Lot{
+					{
+						Instrument:  "XYZ",
+						Quantity:    dec("1"),
+						Buy:         Leg{Date: day(2021, 1, 5), Price: dec("50"), Currency: gonbp.PLN},
+						Sell:        Leg{Date: day(2021, 12, 1), Price: dec("40"), Currency: gonbp.PLN},
+						CostBasis:   dec("50"),
+						Proceeds:    dec("40"),
+						Commissions: dec("2"),
+						Gain:        dec("-12"),
+					},
+					{
+						Instrument:  "ACME",
+						Quantity:    dec("10"),
+						Buy:         Leg{Date: day(2021, 3, 10), Price: dec("100"), Currency: gonbp.USD, Rate: usd0310},
+						Sell:        Leg{Date: day(2022, 4, 19), Price: dec("150"), Currency: gonbp.USD, Rate: usd0419},
+						CostBasis:   dec("3800"),
+						Proceeds:    dec("6429.75"),
+						Commissions: dec("40.43"),
+						Gain:        dec("2589.32"),
+					},
+					{
+						Instrument:  "ACME",
+						Quantity:    dec("2"),
+						Buy:         Leg{Date: day(2021, 6, 15), Price: dec("120"), Currency: gonbp.USD, Rate: usd0615},
+						Sell:        Leg{Date: day(2022, 4, 19), Price: dec("150"), Currency: gonbp.USD, Rate: usd0419},
+						CostBasis:   dec("900"),
+						Proceeds:    dec("1285.95"),
+						Commissions: dec("7.29"),
+						Gain:        dec("378.66"),
+					},
+				},
+				Years: []YearSummary{
+					{Year: 2021, Proceeds: dec("40"), CostBasis: dec("50"), Commissions: dec("2"), Gain: dec("-12")},
+					{Year: 2022, Proceeds: dec("7715.7"), CostBasis: dec("4700"), Commissions: dec("47.72"), Gain: dec("2967.98")},
+				},
+			},
+		},
+		{
+			name: "Short sale",
+			trades: []Trade{
+				{Date: day(2021, 3, 10), Instrument: "ACME", Side: Buy, Quantity: dec("10"), Price: dec("100"), Currency: gonbp.USD},
+				{Date: day(2022, 4, 19), Instrument: "ACME", Side: Sell, Quantity: dec("11"), Price: dec("150"), Currency: gonbp.USD},
+			},
+			wantErr: ErrShortSale,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Calculator{rates: testRates}
+			got, err := c.Calculate(tt.trades)
+			if diff := cmp.Diff(tt.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Calculate() error mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Calculate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
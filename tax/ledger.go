@@ -0,0 +1,133 @@
+package tax
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/igor-kupczynski/gonbp"
+	"github.com/shopspring/decimal"
+)
+
+// Ledger columns, the input ledger has to have the first four, the remaining ones are added to the output
+const (
+	ColumnDate     = "date"
+	ColumnCurrency = "currency"
+	ColumnAmount   = "amount"
+	ColumnKind     = "kind"
+
+	ColumnPLN      = "pln"
+	ColumnRate     = "rate"
+	ColumnTableNo  = "table_no"
+	ColumnRateDate = "rate_date"
+)
+
+// ConvertLedger reads a CSV ledger of transactions and writes it back with the PLN amounts
+//
+// The input starts with a header row naming the date, currency, amount and kind columns, in any order. The dates are
+// formatted as 2006-01-02 and the amounts use a dot as the decimal separator. Every row is converted as in Convert,
+// and written with the input columns followed by the pln, rate, table_no and rate_date columns.
+//
+// The rates are fetched with a single range query per currency covering all of its transactions, and cached before
+// the rows are converted.
+func (c *Converter) ConvertLedger(r io.Reader, w io.Writer) error {
+	return c.ConvertLedgerContext(context.Background(), r, w)
+}
+
+// ConvertLedgerContext is like ConvertLedger, but the NBP API calls are bound to ctx
+func (c *Converter) ConvertLedgerContext(ctx context.Context, r io.Reader, w io.Writer) error {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return fmt.Errorf("can't read ledger: %w", err)
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("can't read ledger: missing header row")
+	}
+	header := records[0]
+	columns, err := ledgerColumns(header)
+	if err != nil {
+		return err
+	}
+
+	type row struct {
+		date   gonbp.Date
+		curr   gonbp.Currency
+		amount decimal.Decimal
+	}
+	rows := make([]row, 0, len(records)-1)
+	periods := make(map[gonbp.Currency][2]gonbp.Date)
+	for i, record := range records[1:] {
+		line := i + 2
+		date, err := gonbp.ParseDate(strings.TrimSpace(record[columns[ColumnDate]]))
+		if err != nil {
+			return fmt.Errorf("line %d: can't parse date: %w", line, err)
+		}
+		curr, err := gonbp.ParseCurrency(record[columns[ColumnCurrency]])
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		amount, err := decimal.NewFromString(strings.TrimSpace(record[columns[ColumnAmount]]))
+		if err != nil {
+			return fmt.Errorf("line %d: can't parse amount: %w", line, err)
+		}
+		rows = append(rows, row{date: date, curr: curr, amount: amount})
+
+		if curr == gonbp.PLN {
+			continue
+		}
+		period, ok := periods[curr]
+		if !ok || date.Before(period[0]) {
+			period[0] = date
+		}
+		if !ok || date.After(period[1]) {
+			period[1] = date
+		}
+		periods[curr] = period
+	}
+
+	// The prefetched period starts the lookback window before the first transaction in a currency, so that it covers
+	// the search for the last business day before it
+	prefetchDays := c.rates.MaxLookback()
+	for curr, period := range periods {
+		if _, err := c.rates.RatesBetweenContext(ctx, curr, period[0].AddDays(-prefetchDays), period[1]); err != nil {
+			return fmt.Errorf("can't fetch %s rates: %w", curr, err)
+		}
+	}
+
+	out := csv.NewWriter(w)
+	if err := out.Write(append(append([]string(nil), header...), ColumnPLN, ColumnRate, ColumnTableNo, ColumnRateDate)); err != nil {
+		return err
+	}
+	for i, row := range rows {
+		conv, err := c.ConvertOnContext(ctx, row.date, row.curr, row.amount)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", i+2, err)
+		}
+		var rate, rateDate string
+		if conv.TableNo != "" {
+			rate, rateDate = conv.Rate.String(), conv.RateDay.String()
+		}
+		record := append(append([]string(nil), records[i+1]...), conv.PLN.StringFixed(2), rate, conv.TableNo, rateDate)
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// ledgerColumns returns the indexes of the required columns in a given header row
+func ledgerColumns(header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{ColumnDate, ColumnCurrency, ColumnAmount, ColumnKind} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("can't read ledger: missing %s column in the header row", name)
+		}
+	}
+	return columns, nil
+}
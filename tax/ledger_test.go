@@ -0,0 +1,156 @@
+package tax
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/igor-kupczynski/gonbp"
+	"github.com/igor-kupczynski/gonbp/cache"
+	"github.com/shopspring/decimal"
+)
+
+func TestConverter_ConvertLedger(t *testing.T) {
+	tests := []struct {
+		name      string
+		ledger    string
+		want      string
+		wantCalls []string
+		wantErr   string
+	}{
+		{
+			name: "Dividends and a PLN fee",
+			ledger: "Kind,Date,Amount,Currency,Note\n" +
+				"dividend,2022-04-19,12.34,EUR,ACME\n" +
+				"fee,2022-04-19,-5,PLN,\n" +
+				"dividend,2022-04-20,-1.5,eur,ACME withholding\n",
+			want: "Kind,Date,Amount,Currency,Note,pln,rate,table_no,rate_date\n" +
+				"dividend,2022-04-19,12.34,EUR,ACME,57.23,4.6378,074/A/NBP/2022,2022-04-15\n" +
+				"fee,2022-04-19,-5,PLN,,-5.00,,,\n" +
+				"dividend,2022-04-20,-1.5,eur,ACME withholding,-6.97,4.6448,075/A/NBP/2022,2022-04-19\n",
+			wantCalls: []string{"EUR/2022-04-05/2022-04-20", "EUR/2022-04-19", "EUR/2022-04-20"},
+		},
+		{
+			name:    "Missing column",
+			ledger:  "date,currency,amount\n2022-04-19,EUR,12.34\n",
+			wantErr: "can't read ledger: missing kind column in the header row",
+		},
+		{
+			name:    "Invalid date",
+			ledger:  "date,currency,amount,kind\n2022-04-19,EUR,12.34,dividend\n19.04.2022,EUR,12.34,dividend\n",
+			wantErr: "line 3: can't parse date",
+		},
+		{
+			name:    "Invalid currency",
+			ledger:  "date,currency,amount,kind\n2022-04-19,EURO,12.34,dividend\n",
+			wantErr: "line 2: invalid currency code",
+		},
+		{
+			name:    "Invalid amount",
+			ledger:  "date,currency,amount,kind\n2022-04-19,EUR,\"12,34\",dividend\n",
+			wantErr: "line 2: can't parse amount",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rates := &mockRates{rates: map[string]*gonbp.Rate{
+				"EUR/2022-04-19": {TableNo: "074/A/NBP/2022", Day: day(2022, 4, 15), Mid: decimal.NewFromFloat(4.6378)},
+				"EUR/2022-04-20": {TableNo: "075/A/NBP/2022", Day: day(2022, 4, 19), Mid: decimal.NewFromFloat(4.6448)},
+			}}
+			c := &Converter{rates: rates}
+			var out bytes.Buffer
+			err := c.ConvertLedger(strings.NewReader(tt.ledger), &out)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ConvertLedger() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ConvertLedger() error = %v, want no error", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, out.String()); diff != "" {
+				t.Errorf("ConvertLedger() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantCalls, rates.calls); diff != "" {
+				t.Errorf("calls mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type countingTransport struct {
+	urls     map[string]string
+	requests []string
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	url := req.URL.String()
+	c.requests = append(c.requests, url)
+	body, ok := c.urls[url]
+	if !ok {
+		return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader("404 NotFound - Not Found - Brak danych"))}, nil
+	}
+	return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func TestConverter_ConvertLedgerBatched(t *testing.T) {
+	transport := &countingTransport{urls: map[string]string{
+		"https://api.nbp.pl/api/exchangerates/rates/A/EUR/2022-04-05/2022-04-20": `{"table":"A","currency":"euro","code":"EUR","rates":[` +
+			`{"no":"073/A/NBP/2022","effectiveDate":"2022-04-14","mid":4.6215},` +
+			`{"no":"074/A/NBP/2022","effectiveDate":"2022-04-15","mid":4.6378},` +
+			`{"no":"075/A/NBP/2022","effectiveDate":"2022-04-19","mid":4.6448}]}`,
+	}}
+	nbp := gonbp.Init("", &http.Client{Transport: transport}, gonbp.WithCache(cache.NewMemory(100)))
+	ledger := "date,currency,amount,kind\n" +
+		"2022-04-19,EUR,12.34,dividend\n" +
+		"2022-04-20,EUR,-1.5,dividend\n"
+
+	var out bytes.Buffer
+	if err := New(nbp).ConvertLedger(strings.NewReader(ledger), &out); err != nil {
+		t.Errorf("ConvertLedger() error = %v, want no error", err)
+		return
+	}
+	want := "date,currency,amount,kind,pln,rate,table_no,rate_date\n" +
+		"2022-04-19,EUR,12.34,dividend,57.23,4.6378,074/A/NBP/2022,2022-04-15\n" +
+		"2022-04-20,EUR,-1.5,dividend,-6.97,4.6448,075/A/NBP/2022,2022-04-19\n"
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("ConvertLedger() mismatch (-want +got):\n%s", diff)
+	}
+	if len(transport.requests) != 1 {
+		t.Errorf("Expected a single NBP API request, got %v", transport.requests)
+	}
+}
+
+func TestConverter_ConvertLedgerLookback(t *testing.T) {
+	transport := &countingTransport{urls: map[string]string{
+		"https://api.nbp.pl/api/exchangerates/rates/A/EUR/2022-03-20/2022-04-19": `{"table":"A","currency":"euro","code":"EUR","rates":[` +
+			`{"no":"074/A/NBP/2022","effectiveDate":"2022-04-15","mid":4.6378}]}`,
+	}}
+	nbp := gonbp.Init(
+		"",
+		&http.Client{Transport: transport},
+		gonbp.WithCache(cache.NewMemory(100)),
+		gonbp.WithMaxLookback(30),
+	)
+	ledger := "date,currency,amount,kind\n2022-04-19,EUR,12.34,dividend\n"
+
+	var out bytes.Buffer
+	if err := New(nbp).ConvertLedger(strings.NewReader(ledger), &out); err != nil {
+		t.Errorf("ConvertLedger() error = %v, want no error", err)
+		return
+	}
+	want := "date,currency,amount,kind,pln,rate,table_no,rate_date\n" +
+		"2022-04-19,EUR,12.34,dividend,57.23,4.6378,074/A/NBP/2022,2022-04-15\n"
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("ConvertLedger() mismatch (-want +got):\n%s", diff)
+	}
+	if len(transport.requests) != 1 {
+		t.Errorf("Expected a single NBP API request, got %v", transport.requests)
+	}
+}
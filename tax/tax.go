@@ -0,0 +1,105 @@
+// Package tax converts foreign currency amounts to PLN following the Polish income tax (PIT) rules
+//
+// The amounts are converted with the NBP mid rate from the last business day before the day the income or the cost
+// is incurred, as required by art. 11a ust. 1 of the PIT act (ustawa o podatku dochodowym od osób fizycznych).
+package tax
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/igor-kupczynski/gonbp"
+	"github.com/shopspring/decimal"
+)
+
+type rateSource interface {
+	PreviousRateOnContext(ctx context.Context, curr gonbp.Currency, day gonbp.Date) (*gonbp.Rate, error)
+	RatesBetweenContext(ctx context.Context, curr gonbp.Currency, from, to gonbp.Date) ([]gonbp.Rate, error)
+	MaxLookback() int
+}
+
+// Converter converts the transaction amounts to PLN with the NBP mid rates
+type Converter struct {
+	rates rateSource
+}
+
+// New returns *Converter instance using a given *gonbp.NBP instance
+//
+// The rates come from NBP table A, unless a different table is selected with gonbp.NBP.WithTable.
+func New(nbp *gonbp.NBP) *Converter {
+	return &Converter{rates: nbp}
+}
+
+// Conversion is the PLN value of a transaction, with the details of the rate applied
+type Conversion struct {
+	// Date is the transaction date
+	Date     gonbp.Date
+	Currency gonbp.Currency
+	Amount   decimal.Decimal
+	// Rate is the NBP mid rate applied, zero for PLN transactions
+	Rate decimal.Decimal
+	// TableNo is the number of the NBP table the rate comes from, empty for PLN transactions
+	TableNo string
+	// RateDay is the effective date of the rate, zero for PLN transactions
+	RateDay gonbp.Date
+	// PLN is the amount in PLN, rounded half-up to grosze
+	PLN decimal.Decimal
+	// Rule is a short statement of the rule applied, in Polish, e.g. for a tax workpaper
+	Rule string
+}
+
+// Convert is like ConvertOn for the civil date of date
+func (c *Converter) Convert(date time.Time, curr gonbp.Currency, amount decimal.Decimal) (*Conversion, error) {
+	return c.ConvertOnContext(context.Background(), gonbp.DateOf(date), curr, amount)
+}
+
+// ConvertContext is like Convert, but the NBP API calls are bound to ctx
+func (c *Converter) ConvertContext(ctx context.Context, date time.Time, curr gonbp.Currency, amount decimal.Decimal) (*Conversion, error) {
+	return c.ConvertOnContext(ctx, gonbp.DateOf(date), curr, amount)
+}
+
+// ConvertOn returns the PLN value of an amount in a given currency for a transaction on a given date
+//
+// The amount is converted with the mid rate from the last business day before the transaction date. Amounts in PLN
+// are returned as they are.
+func (c *Converter) ConvertOn(date gonbp.Date, curr gonbp.Currency, amount decimal.Decimal) (*Conversion, error) {
+	return c.ConvertOnContext(context.Background(), date, curr, amount)
+}
+
+// ConvertOnContext is like ConvertOn, but the NBP API calls are bound to ctx
+func (c *Converter) ConvertOnContext(ctx context.Context, date gonbp.Date, curr gonbp.Currency, amount decimal.Decimal) (*Conversion, error) {
+	if curr == gonbp.PLN {
+		return &Conversion{
+			Date:     date,
+			Currency: curr,
+			Amount:   amount,
+			PLN:      gonbp.RoundHalfUp.Round(amount, 2),
+			Rule:     "Kwota w PLN, bez przeliczenia.",
+		}, nil
+	}
+	rate, err := c.rates.PreviousRateOnContext(ctx, curr, date)
+	if err != nil {
+		return nil, fmt.Errorf("can't convert %s %s from %s: %w", amount, curr, date, err)
+	}
+	return &Conversion{
+		Date:     date,
+		Currency: curr,
+		Amount:   amount,
+		Rate:     rate.Mid,
+		TableNo:  rate.TableNo,
+		RateDay:  rate.Day,
+		PLN:      gonbp.RoundHalfUp.Round(amount.Mul(rate.Mid), 2),
+		Rule:     rule(date, curr, rate),
+	}, nil
+}
+
+func rule(date gonbp.Date, curr gonbp.Currency, rate *gonbp.Rate) string {
+	return fmt.Sprintf(
+		"Przeliczono po średnim kursie NBP z ostatniego dnia roboczego poprzedzającego dzień %s "+
+			"(art. 11a ust. 1 ustawy o PIT): tabela nr %s z dnia %s, 1 %s = %s PLN.",
+		date, rate.TableNo, rate.Day, curr,
+		strings.Replace(rate.Mid.String(), ".", ",", 1),
+	)
+}
@@ -0,0 +1,111 @@
+package tax
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/igor-kupczynski/gonbp"
+	"github.com/shopspring/decimal"
+)
+
+func day(year, month, day int) gonbp.Date {
+	return gonbp.NewDate(year, time.Month(month), day)
+}
+
+// mockRates returns the previous rates keyed by currency and the transaction date
+type mockRates struct {
+	rates map[string]*gonbp.Rate
+	calls []string
+}
+
+func (m *mockRates) PreviousRateOnContext(ctx context.Context, curr gonbp.Currency, day gonbp.Date) (*gonbp.Rate, error) {
+	key := fmt.Sprintf("%s/%s", curr, day)
+	m.calls = append(m.calls, key)
+	rate, ok := m.rates[key]
+	if !ok {
+		panic("response not set up for " + key)
+	}
+	if rate == nil {
+		return nil, gonbp.ErrNoExchangeRateForGivenDay
+	}
+	return rate, nil
+}
+
+func (m *mockRates) MaxLookback() int {
+	return gonbp.DefaultMaxLookback
+}
+
+func (m *mockRates) RatesBetweenContext(ctx context.Context, curr gonbp.Currency, from, to gonbp.Date) ([]gonbp.Rate, error) {
+	m.calls = append(m.calls, fmt.Sprintf("%s/%s/%s", curr, from, to))
+	return nil, nil
+}
+
+func TestConverter_Convert(t *testing.T) {
+	rates := &mockRates{rates: map[string]*gonbp.Rate{
+		"EUR/2022-04-19": {TableNo: "074/A/NBP/2022", Day: day(2022, 4, 15), Mid: decimal.NewFromFloat(4.6378)},
+		"USD/1999-01-04": nil,
+	}}
+	tests := []struct {
+		name    string
+		date    gonbp.Date
+		curr    gonbp.Currency
+		amount  string
+		want    *Conversion
+		wantErr error
+	}{
+		{
+			name:   "Dividend after a long weekend",
+			date:   day(2022, 4, 19),
+			curr:   gonbp.EUR,
+			amount: "12.34",
+			want: &Conversion{
+				Date:     day(2022, 4, 19),
+				Currency: gonbp.EUR,
+				Amount:   decimal.RequireFromString("12.34"),
+				Rate:     decimal.NewFromFloat(4.6378),
+				TableNo:  "074/A/NBP/2022",
+				RateDay:  day(2022, 4, 15),
+				PLN:      decimal.RequireFromString("57.23"),
+				Rule: "Przeliczono po średnim kursie NBP z ostatniego dnia roboczego poprzedzającego dzień 2022-04-19 " +
+					"(art. 11a ust. 1 ustawy o PIT): tabela nr 074/A/NBP/2022 z dnia 2022-04-15, 1 EUR = 4,6378 PLN.",
+			},
+		},
+		{
+			name:   "PLN amount",
+			date:   day(2022, 4, 19),
+			curr:   gonbp.PLN,
+			amount: "100.005",
+			want: &Conversion{
+				Date:     day(2022, 4, 19),
+				Currency: gonbp.PLN,
+				Amount:   decimal.RequireFromString("100.005"),
+				PLN:      decimal.RequireFromString("100.01"),
+				Rule:     "Kwota w PLN, bez przeliczenia.",
+			},
+		},
+		{
+			name:    "No rate",
+			date:    day(1999, 1, 4),
+			curr:    gonbp.USD,
+			amount:  "1",
+			wantErr: gonbp.ErrNoExchangeRateForGivenDay,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Converter{rates: rates}
+			got, err := c.ConvertOn(tt.date, tt.curr, decimal.RequireFromString(tt.amount))
+			if diff := cmp.Diff(tt.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ConvertOn() error mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ConvertOn() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}